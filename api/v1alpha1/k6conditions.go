@@ -55,6 +55,71 @@ const (
 	// - if False, it's a PLZ test run and it wasn't aborted.
 	// - if True, it is a PLZ test run and it was aborted.
 	CloudTestRunAborted = "CloudTestRunAborted"
+
+	// ReportGenerated indicates if the reports configured in spec.report were generated.
+	// - if empty / Unknown, no reports are configured
+	// - if False, reports are to be generated once all runners stop; if the message is set,
+	// the reports cannot be stored because of an existing ConfigMap not controlled by the test run
+	// - if True, reports have been generated and stored
+	ReportGenerated = "ReportGenerated"
+
+	// InitializerFailed indicates if the initializer rejected the test run.
+	// - if empty / Unknown, the initializer hasn't failed
+	// - if True, the initializer has failed and the message contains the error from k6,
+	//   or the generated script cannot be stored, e.g. because it doesn't fit into a ConfigMap
+	InitializerFailed = "InitializerFailed"
+
+	// TestRunPaused indicates if the test run was paused by an alert from spec.alertActions.
+	// - if empty / Unknown, no alert has paused the test run
+	// - if False, the test run was resumed after its alerts were resolved
+	// - if True, the test run is paused and the message contains the alert
+	TestRunPaused = "TestRunPaused"
+
+	// RunnersAdmitted indicates if the runner jobs were admitted by the Kueue queue from spec.queueName.
+	// - if empty / Unknown, no queue is configured
+	// - if False, the runner jobs are suspended until admitted and the message contains the progress
+	// - if True, all runner jobs have been admitted
+	RunnersAdmitted = "RunnersAdmitted"
+
+	// RunnersScheduled indicates if the PodGroup of spec.gangScheduling was scheduled.
+	// - if empty / Unknown, gang scheduling is not configured
+	// - if False, the runners wait to be scheduled all at once and the message contains the phase of the PodGroup,
+	//   or the PodGroup failed to be scheduled and the TestRun is in the error stage
+	// - if True, the gang scheduler has scheduled the PodGroup and the message contains its phase
+	RunnersScheduled = "RunnersScheduled"
+
+	// StartSkewExceeded is a warning about runners that started too far apart.
+	// - if empty / Unknown, the start of the runners hasn't been measured yet
+	// - if False, the runners started within spec.maxStartSkew
+	// - if True, the spread of their start exceeds spec.maxStartSkew and the message contains it
+	StartSkewExceeded = "StartSkewExceeded"
+
+	// SpreadUnsatisfiable is a warning about spec.spread or spec.separate that current nodes cannot satisfy.
+	// - if empty / Unknown, no spread is configured or the nodes couldn't be checked
+	// - if False, the nodes can satisfy the spread
+	// - if True, runners are likely to stay pending until nodes are added and the message contains the reason
+	SpreadUnsatisfiable = "SpreadUnsatisfiable"
+
+	// ScriptGranted indicates if the script ConfigMap from another namespace may be used.
+	// - if empty / Unknown, the script is in the namespace of the test run
+	// - if False, no ScriptGrant allows the namespace of the test run to use it and the message names the ConfigMap,
+	// or the message names the existing copy that is not controlled by the test run
+	// - if True, the script was copied into the namespace of the test run
+	ScriptGranted = "ScriptGranted"
+
+	// PermissionsGranted indicates if spec.runner.permissions were granted to the runners.
+	// - if empty / Unknown, no permissions are requested
+	// - if False, the permissions cannot be granted and the message contains the rules not allowed
+	// by TestRunPolicies or the existing object that is not controlled by the test run
+	// - if True, the ServiceAccount, Role and RoleBinding of the runners were created
+	PermissionsGranted = "PermissionsGranted"
+
+	// EgressRestricted indicates if egress of the runners was restricted to spec.targets.
+	// - if empty / Unknown, egress of the runners is not restricted
+	// - if False, egress cannot be restricted and the message contains the targets not allowed
+	// by TestRunPolicies or the existing NetworkPolicy that is not controlled by the test run
+	// - if True, the NetworkPolicy of the runners was created
+	EgressRestricted = "EgressRestricted"
 )
 
 // Initialize defines only conditions common to all test runs.
@@ -86,6 +151,18 @@ func Initialize(k6 *TestRun) {
 
 	UpdateCondition(k6, CloudTestRunAborted, metav1.ConditionFalse)
 
+	if k6.GetSpec().Report.JUnit {
+		UpdateCondition(k6, ReportGenerated, metav1.ConditionFalse)
+	}
+
+	if len(k6.GetSpec().QueueName) > 0 {
+		UpdateCondition(k6, RunnersAdmitted, metav1.ConditionFalse)
+	}
+
+	if k6.GetSpec().GangScheduling != nil {
+		UpdateCondition(k6, RunnersScheduled, metav1.ConditionFalse)
+	}
+
 	// PLZ test run case
 	if len(k6.GetSpec().TestRunID) > 0 {
 		UpdateCondition(k6, CloudPLZTestRun, metav1.ConditionTrue)
@@ -104,6 +181,11 @@ func UpdateCondition(k6 *TestRun, conditionType string, conditionStatus metav1.C
 	types.UpdateCondition(&k6.GetStatus().Conditions, conditionType, conditionStatus)
 }
 
+// UpdateConditionWithMessage is UpdateCondition with a human-readable message.
+func UpdateConditionWithMessage(k6 *TestRun, conditionType string, conditionStatus metav1.ConditionStatus, message string) {
+	types.UpdateConditionWithMessage(&k6.GetStatus().Conditions, conditionType, conditionStatus, message)
+}
+
 func IsTrue(k6 *TestRun, conditionType string) bool {
 	return meta.IsStatusConditionTrue(k6.GetStatus().Conditions, conditionType)
 }
@@ -148,6 +230,50 @@ func (k6status *TestRunStatus) SetIfNewer(proposedStatus TestRunStatus) (isNewer
 			return
 		})
 
+	// artifacts are only ever added, independently of any condition
+	for _, artifact := range proposedStatus.Artifacts {
+		if !hasArtifact(k6status.Artifacts, artifact.Name) {
+			k6status.Artifacts = append(k6status.Artifacts, artifact)
+			isNewer = true
+		}
+	}
+
+	// errors of the runners are collected only once
+	if k6status.RunnerErrors == nil && proposedStatus.RunnerErrors != nil {
+		k6status.RunnerErrors = proposedStatus.RunnerErrors
+		isNewer = true
+	}
+
+	// and so are the results
+	if k6status.Results == nil && proposedStatus.Results != nil {
+		k6status.Results = proposedStatus.Results
+		isNewer = true
+	}
+
+	// resource usage only grows with samples and is completed once
+	if u, proposed := k6status.ResourceUsage, proposedStatus.ResourceUsage; proposed != nil &&
+		(u == nil || proposed.Samples > u.Samples ||
+			(u.PerVU == nil && proposed.PerVU != nil) ||
+			(len(u.AppliedFrom) == 0 && len(proposed.AppliedFrom) > 0) ||
+			(u.Applied == nil && proposed.Applied != nil)) {
+		k6status.ResourceUsage = proposed
+		isNewer = true
+	}
+
+	// start of the runners is measured once
+	if k6status.StartSkew == nil && proposedStatus.StartSkew != nil {
+		k6status.StartSkew = proposedStatus.StartSkew
+		isNewer = true
+	}
+
+	// replacements of runners are only added and completed: started or given up
+	if len(proposedStatus.ReplacedRunners) > len(k6status.ReplacedRunners) ||
+		(len(proposedStatus.ReplacedRunners) == len(k6status.ReplacedRunners) &&
+			completedReplacements(proposedStatus.ReplacedRunners) > completedReplacements(k6status.ReplacedRunners)) {
+		k6status.ReplacedRunners = proposedStatus.ReplacedRunners
+		isNewer = true
+	}
+
 	// If a change in stage is proposed, confirm that it is consistent with
 	// expected flow of any test run.
 	if k6status.Stage != proposedStatus.Stage && len(proposedStatus.Stage) > 0 {
@@ -188,3 +314,21 @@ func (k6status *TestRunStatus) SetIfNewer(proposedStatus TestRunStatus) (isNewer
 
 	return
 }
+
+func hasArtifact(artifacts []Artifact, name string) bool {
+	for _, artifact := range artifacts {
+		if artifact.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func completedReplacements(replacements []RunnerReplacement) (n int) {
+	for _, replacement := range replacements {
+		if replacement.ReplacedAt != nil || replacement.GaveUp != "" {
+			n++
+		}
+	}
+	return
+}
@@ -0,0 +1,31 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetIfNewerArtifacts(t *testing.T) {
+	script := Artifact{Name: "script", ConfigMap: "test-generated-script", Key: "test.js"}
+	report := Artifact{Name: "junit", ConfigMap: "test-report", Key: "junit.xml"}
+
+	status := TestRunStatus{Stage: "initialization"}
+
+	// the generated script is stored before any report
+	proposed := *status.DeepCopy()
+	proposed.Artifacts = []Artifact{script}
+	assert.True(t, status.SetIfNewer(proposed), "artifact without a condition change should be accepted")
+	assert.Equal(t, []Artifact{script}, status.Artifacts)
+
+	assert.False(t, status.SetIfNewer(proposed), "known artifact should not be a change")
+
+	// a stale status doesn't drop artifacts
+	assert.False(t, status.SetIfNewer(TestRunStatus{Stage: "initialization"}))
+	assert.Equal(t, []Artifact{script}, status.Artifacts)
+
+	proposed = *status.DeepCopy()
+	proposed.Artifacts = append(proposed.Artifacts, report)
+	assert.True(t, status.SetIfNewer(proposed))
+	assert.Equal(t, []Artifact{script, report}, status.Artifacts)
+}
@@ -38,6 +38,7 @@ type PrivateLoadZoneSpec struct {
 	NodeSelector       map[string]string             `json:"nodeSelector,omitempty"`
 	Image              string                        `json:"image,omitempty"`
 	ImagePullSecrets   []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	SecurityProfile    SecurityProfile               `json:"securityProfile,omitempty"`
 }
 
 // PrivateLoadZoneStatus defines the observed state of PrivateLoadZone
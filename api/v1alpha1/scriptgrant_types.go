@@ -0,0 +1,93 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScriptGrantSpec defines which TestRuns may reference scripts
+// in the namespace of the ScriptGrant
+type ScriptGrantSpec struct {
+	// From lists namespaces of TestRuns allowed to reference the scripts.
+	// +kubebuilder:validation:MinItems=1
+	From []ScriptGrantFrom `json:"from"`
+	// To lists the scripts that may be referenced.
+	// +kubebuilder:validation:MinItems=1
+	To []ScriptGrantTo `json:"to"`
+}
+
+// ScriptGrantFrom describes a namespace of TestRuns
+type ScriptGrantFrom struct {
+	Namespace string `json:"namespace"`
+}
+
+// ScriptGrantTo describes scripts that may be referenced
+type ScriptGrantTo struct {
+	// +kubebuilder:validation:Enum=ConfigMap
+	Kind string `json:"kind"`
+	// Name of the object. If empty, all objects of the kind are granted.
+	Name string `json:"name,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ScriptGrant is the Schema for the scriptgrants API.
+// It allows TestRuns in other namespaces to use scripts of its namespace,
+// similarly to ReferenceGrant of Gateway API.
+type ScriptGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScriptGrantSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ScriptGrantList contains a list of ScriptGrant
+type ScriptGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScriptGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScriptGrant{}, &ScriptGrantList{})
+}
+
+// Allows tells whether the grant allows TestRuns in the namespace
+// to reference the object of the kind.
+func (g *ScriptGrant) Allows(namespace, kind, name string) bool {
+	fromAllowed := false
+	for _, from := range g.Spec.From {
+		if from.Namespace == namespace {
+			fromAllowed = true
+			break
+		}
+	}
+	if !fromAllowed {
+		return false
+	}
+
+	for _, to := range g.Spec.To {
+		if to.Kind == kind && (len(to.Name) == 0 || to.Name == name) {
+			return true
+		}
+	}
+
+	return false
+}
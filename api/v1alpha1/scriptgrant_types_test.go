@@ -0,0 +1,45 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScriptGrantAllows(t *testing.T) {
+	grant := &ScriptGrant{Spec: ScriptGrantSpec{
+		From: []ScriptGrantFrom{{Namespace: "team-a"}, {Namespace: "team-b"}},
+		To:   []ScriptGrantTo{{Kind: "ConfigMap", Name: "checkout"}},
+	}}
+	all := &ScriptGrant{Spec: ScriptGrantSpec{
+		From: []ScriptGrantFrom{{Namespace: "team-a"}},
+		To:   []ScriptGrantTo{{Kind: "ConfigMap"}},
+	}}
+
+	tests := []struct {
+		name      string
+		grant     *ScriptGrant
+		namespace string
+		kind      string
+		script    string
+		allowed   bool
+	}{
+		{"granted script", grant, "team-a", "ConfigMap", "checkout", true},
+		{"second namespace", grant, "team-b", "ConfigMap", "checkout", true},
+		{"namespace not granted", grant, "team-c", "ConfigMap", "checkout", false},
+		{"empty namespace", grant, "", "ConfigMap", "checkout", false},
+		{"other script", grant, "team-a", "ConfigMap", "login", false},
+		{"other kind", grant, "team-a", "Secret", "checkout", false},
+		{"empty name grants all scripts", all, "team-a", "ConfigMap", "login", true},
+		{"empty name grants only the kind", all, "team-a", "Secret", "login", false},
+		{"empty name grants only the namespaces", all, "team-b", "ConfigMap", "login", false},
+		{"no namespaces", &ScriptGrant{Spec: ScriptGrantSpec{To: all.Spec.To}}, "team-a", "ConfigMap", "login", false},
+		{"no scripts", &ScriptGrant{Spec: ScriptGrantSpec{From: all.Spec.From}}, "team-a", "ConfigMap", "login", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.allowed, test.grant.Allows(test.namespace, test.kind, test.script))
+		})
+	}
+}
@@ -18,13 +18,18 @@ import (
 	"errors"
 	"github.com/grafana/k6-operator/pkg/types"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"path/filepath"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
 )
 
+const defaultMaxStartSkew = time.Second
+
 type PodMetadata struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
@@ -51,6 +56,12 @@ type Pod struct {
 	InitContainers               []InitContainer                   `json:"initContainers,omitempty"`
 	Volumes                      []corev1.Volume                   `json:"volumes,omitempty"`
 	VolumeMounts                 []corev1.VolumeMount              `json:"volumeMounts,omitempty"`
+
+	// Permissions are granted to the runners for scripts calling the Kubernetes API,
+	// e.g. with xk6-kubernetes or xk6-disruptor. The operator creates a ServiceAccount,
+	// Role and RoleBinding owned by the TestRun, if all rules are allowed by a
+	// TestRunPolicy in the namespace. Only for runners.
+	Permissions []rbacv1.PolicyRule `json:"permissions,omitempty"`
 }
 
 type InitContainer struct {
@@ -81,7 +92,8 @@ type K6Scuttle struct {
 type TestRunSpec struct {
 	Script      K6Script               `json:"script"`
 	Parallelism int32                  `json:"parallelism"`
-	Separate    bool                   `json:"separate,omitempty"`
+	Separate    bool                   `json:"separate,omitempty"` // Deprecated: use Spread
+	Spread      *Spread                `json:"spread,omitempty"`
 	Arguments   string                 `json:"arguments,omitempty"`
 	Ports       []corev1.ContainerPort `json:"ports,omitempty"`
 	Initializer *Pod                   `json:"initializer,omitempty"`
@@ -91,6 +103,43 @@ type TestRunSpec struct {
 	Paused      string                 `json:"paused,omitempty"`
 	Scuttle     K6Scuttle              `json:"scuttle,omitempty"`
 	Cleanup     Cleanup                `json:"cleanup,omitempty"`
+	Report      Report                 `json:"report,omitempty"`
+
+	// AlertActions pause or stop the test run when matching alerts are
+	// received by the Alertmanager webhook of the operator.
+	AlertActions []AlertAction `json:"alertActions,omitempty"`
+
+	// RightSizing enables observing resource usage of the runners through
+	// the metrics API and recommending resources for the next runs.
+	RightSizing *RightSizing `json:"rightSizing,omitempty"`
+
+	// QueueName is the name of the Kueue LocalQueue admitting the runners.
+	// If set, the runner jobs are created suspended and the test run waits
+	// in the created stage until all of them are admitted.
+	QueueName string `json:"queueName,omitempty"`
+
+	// GangScheduling schedules all runners at once or none of them,
+	// with a PodGroup of a gang scheduler.
+	GangScheduling *GangScheduling `json:"gangScheduling,omitempty"`
+
+	// Termination configures how the runners are stopped and deleted.
+	Termination *Termination `json:"termination,omitempty"`
+
+	// MaxStartSkew is the largest acceptable time between the first and the
+	// last runner starting. A larger skew sets the StartSkewExceeded condition.
+	// Defaults to 1s.
+	MaxStartSkew *metav1.Duration `json:"maxStartSkew,omitempty"`
+
+	// ReplaceLostRunners launches a replacement of a runner lost during the
+	// test, e.g. to node maintenance, with the same execution segment. It
+	// requires all scenarios of the script to use duration-based executors.
+	ReplaceLostRunners bool `json:"replaceLostRunners,omitempty"`
+
+	// Targets restrict egress of the runners with a NetworkPolicy to the
+	// listed targets, DNS and the configured outputs.
+	Targets []Target `json:"targets,omitempty"`
+
+	SecurityProfile SecurityProfile `json:"securityProfile,omitempty"`
 
 	TestRunID string `json:"testRunId,omitempty"` // PLZ reserved field
 	Token     string `json:"token,omitempty"`     // PLZ reserved field (for now)
@@ -101,6 +150,20 @@ type K6Script struct {
 	VolumeClaim K6VolumeClaim `json:"volumeClaim,omitempty"`
 	ConfigMap   K6Configmap   `json:"configMap,omitempty"`
 	LocalFile   string        `json:"localFile,omitempty"`
+	// HAR is a browser recording converted to the script with `har-to-k6`
+	// by the initializer.
+	HAR *K6ScriptConversion `json:"har,omitempty"`
+	// OpenAPI is an API spec converted to the script with the k6 generator
+	// of `openapi-generator-cli` by the initializer.
+	OpenAPI *K6ScriptConversion `json:"openapi,omitempty"`
+}
+
+// K6ScriptConversion describes the source the script is generated from
+type K6ScriptConversion struct {
+	// ConfigMap with the source, in the namespace of the TestRun.
+	ConfigMap K6Configmap `json:"configMap"`
+	// Options are additional arguments of the converter.
+	Options string `json:"options,omitempty"`
 }
 
 // K6VolumeClaim describes the volume claim script location
@@ -114,14 +177,131 @@ type K6VolumeClaim struct {
 type K6Configmap struct {
 	Name string `json:"name"`
 	File string `json:"file,omitempty"`
+	// Namespace of the ConfigMap, if it differs from the namespace of the TestRun.
+	// Such a ConfigMap must be granted with a ScriptGrant in its namespace.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Report describes which reports should be generated at the end of the test run
+type Report struct {
+	// JUnit enables a JUnit XML report of thresholds and checks,
+	// merged from all runners.
+	JUnit bool `json:"junit,omitempty"`
+}
+
+// RightSizing describes how resource usage of the runners is used
+type RightSizing struct {
+	// Apply replaces spec.runner.resources with the recommendation of the
+	// latest finished TestRun of the same trigger or script, scaled to the
+	// number of VUs per runner of this TestRun.
+	Apply bool `json:"apply,omitempty"`
+}
+
+// GangScheduling describes all-or-nothing scheduling of the runners
+type GangScheduling struct {
+	// Scheduler is the gang scheduler owning the PodGroup: `coscheduling`
+	// of scheduler-plugins (the default) or `volcano`.
+	Scheduler GangScheduler `json:"scheduler,omitempty"`
+	// SchedulerName of the runner pods. Defaults to `scheduler-plugins-scheduler`
+	// for coscheduling and to `volcano` for Volcano.
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// ScheduleTimeoutSeconds is how long the coscheduling plugin waits for
+	// all runners to be schedulable before rejecting the group.
+	// +kubebuilder:validation:Minimum=1
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty"`
+	// Queue is the Volcano queue of the PodGroup.
+	Queue string `json:"queue,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=coscheduling;volcano
+type GangScheduler string
+
+const (
+	GangCoscheduling GangScheduler = "coscheduling"
+	GangVolcano      GangScheduler = "volcano"
+)
+
+// Target is a destination of the traffic of the runners, either a host or a CIDR
+type Target struct {
+	// Host is a hostname, resolved to its addresses when the NetworkPolicy is
+	// created. In TestRunPolicies, a `*.` prefix matches all subdomains.
+	Host string `json:"host,omitempty"`
+	// CIDR is a range of IP addresses, e.g. `10.20.0.0/16`.
+	CIDR string `json:"cidr,omitempty"`
+	// Ports of the target, over TCP. All ports are allowed if empty.
+	Ports []int32 `json:"ports,omitempty"`
+}
+
+// Termination describes graceful stopping of the runners
+type Termination struct {
+	// GracePeriodSeconds is the termination grace period of the runner pods:
+	// time for k6 to stop and flush its outputs after SIGTERM. Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+	// TimeoutSeconds is how long runners still running are given to stop
+	// and flush their outputs after a REST stop, before their jobs are
+	// deleted anyway. Defaults to 30.
+	// +kubebuilder:validation:Minimum=0
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// AlertAction describes what happens to the test run when an alert fires
+type AlertAction struct {
+	// Selector matches labels of the alert.
+	Selector metav1.LabelSelector `json:"selector"`
+	// Action is done on the runners through their REST API.
+	Action AlertActionType `json:"action"`
+	// AutoResume resumes the paused test run once all alerts that paused it
+	// are resolved.
+	AutoResume bool `json:"autoResume,omitempty"`
+}
+
+// AlertActionType is what is done to the runners when an alert fires
+// +kubebuilder:validation:Enum=Pause;Stop
+type AlertActionType string
+
+const (
+	AlertPause AlertActionType = "Pause"
+	AlertStop  AlertActionType = "Stop"
+)
+
+// Spread describes how runners are spread across the topology domains of the cluster
+type Spread struct {
+	// TopologyKey is a node label defining the topology domains. `hostname` and `zone`
+	// are shortcuts for the well-known labels. Defaults to `hostname`.
+	TopologyKey string `json:"topologyKey,omitempty"`
+	// Mode tells whether runners must be spread (`required`, the default)
+	// or spreading is only a preference for the scheduler (`preferred`).
+	Mode SpreadMode `json:"mode,omitempty"`
+	// MaxSkew is the maximum allowed difference in number of runners between any
+	// two domains. If not set, there is at most one runner per domain.
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
 }
 
+// SpreadMode describes whether spreading of runners is enforced
+// +kubebuilder:validation:Enum=required;preferred
+type SpreadMode string
+
+const (
+	SpreadRequired  SpreadMode = "required"
+	SpreadPreferred SpreadMode = "preferred"
+)
+
 //TODO: cleanup pre-execution?
 
 // Cleanup allows for automatic cleanup of resources post execution
 // +kubebuilder:validation:Enum=post
 type Cleanup string
 
+// SecurityProfile is a predefined set of security settings applied to
+// all pods generated by the operator, on top of user-provided settings.
+// `restricted` complies with the restricted Pod Security Standard.
+// +kubebuilder:validation:Enum=restricted
+type SecurityProfile string
+
+const SecurityProfileRestricted SecurityProfile = "restricted"
+
 // Stage describes which stage of the test execution lifecycle our runners are in
 // +kubebuilder:validation:Enum=initialization;initialized;created;started;stopped;finished;error
 type Stage string
@@ -132,9 +312,141 @@ type TestRunStatus struct {
 	TestRunID       string `json:"testRunId,omitempty"`
 	AggregationVars string `json:"aggregationVars,omitempty"`
 
+	// Artifacts lists files produced by the operator for this test run.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+
+	// RunnerErrors summarizes errors logged by the runners. It is set once
+	// the runners have finished.
+	RunnerErrors *RunnerErrors `json:"runnerErrors,omitempty"`
+
+	// Results summarize the outcome of the test run. They are collected
+	// together with the reports in spec.report.
+	Results *TestRunResults `json:"results,omitempty"`
+
+	// Alerts that matched spec.alertActions.
+	Alerts []AlertRecord `json:"alerts,omitempty"`
+
+	// ResourceUsage of the runners, observed if spec.rightSizing is set.
+	ResourceUsage *ResourceUsage `json:"resourceUsage,omitempty"`
+
+	// StartSkew tells when the runners started, as measured by the starter.
+	StartSkew *StartSkew `json:"startSkew,omitempty"`
+
+	// ReplacedRunners lists runners replaced because of spec.replaceLostRunners.
+	ReplacedRunners []RunnerReplacement `json:"replacedRunners,omitempty"`
+
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// Artifact references a file stored by the operator in a ConfigMap.
+type Artifact struct {
+	Name      string `json:"name"`
+	ConfigMap string `json:"configMap"`
+	Key       string `json:"key"`
+}
+
+// TestRunResults summarize the outcome of the test run
+type TestRunResults struct {
+	// ThresholdsPassed tells whether all thresholds of the test have passed.
+	ThresholdsPassed bool `json:"thresholdsPassed"`
+	// FailedThresholds lists failed thresholds as `metric: expression`.
+	FailedThresholds []string `json:"failedThresholds,omitempty"`
+	// Metrics are values of the key metrics and of the metrics with thresholds,
+	// keyed by `metric.aggregation`, e.g. `http_req_duration.p(95)`.
+	Metrics map[string]string `json:"metrics,omitempty"`
+}
+
+// StartSkew describes how far apart in time the runners started
+type StartSkew struct {
+	// Runners maps runner jobs to when they started, as an offset from the
+	// first start request of the starter, e.g. `4.2ms`.
+	Runners map[string]string `json:"runners,omitempty"`
+	// Min is the offset of the first runner to start.
+	Min string `json:"min"`
+	// Max is the offset of the last runner to start.
+	Max string `json:"max"`
+	// Spread is the time between the first and the last runner starting.
+	Spread string `json:"spread"`
+}
+
+// RunnerReplacement describes a lost runner and its replacement
+type RunnerReplacement struct {
+	// Runner is the index of the runner, its `instance_id` tag.
+	Runner int `json:"runner"`
+	// LostJob is the name of the job of the lost runner.
+	LostJob string `json:"lostJob"`
+	// Job is the name of the replacement job, empty if the runner was not
+	// replaced.
+	Job string `json:"job,omitempty"`
+	// LostAt is when the loss of the runner was noticed.
+	LostAt metav1.Time `json:"lostAt"`
+	// ReplacedAt is when the replacement started executing.
+	ReplacedAt *metav1.Time `json:"replacedAt,omitempty"`
+	// Gap is the time the segment of the runner was without load, from
+	// LostAt to ReplacedAt.
+	Gap string `json:"gap,omitempty"`
+	// GaveUp tells why the runner was not replaced or why its replacement was
+	// given up on before it started executing.
+	GaveUp string `json:"gaveUp,omitempty"`
+}
+
+// ResourceUsage describes CPU and memory used by the runners
+type ResourceUsage struct {
+	// Key identifies TestRuns that can share recommendations: it is either
+	// the trigger of the TestRun or the hash of its script.
+	Key string `json:"key,omitempty"`
+	// AppliedFrom is the TestRun whose recommendation was applied to the runners.
+	AppliedFrom string `json:"appliedFrom,omitempty"`
+	// Samples is the number of times the metrics API was queried.
+	Samples        int32        `json:"samples,omitempty"`
+	LastSampleTime *metav1.Time `json:"lastSampleTime,omitempty"`
+	// Runners lists usage of each runner pod.
+	Runners []RunnerUsage `json:"runners,omitempty"`
+	// PerVU is the usage of the busiest runner divided by its number of VUs.
+	// It is set once the runners have finished.
+	PerVU *RunnerUsage `json:"perVU,omitempty"`
+	// Recommended resources of the runners for the same number of VUs per runner.
+	Recommended *corev1.ResourceRequirements `json:"recommended,omitempty"`
+	// Applied resources of the runners: the recommendation of AppliedFrom
+	// merged into spec.runner.resources. Runner jobs use them when set.
+	Applied *corev1.ResourceRequirements `json:"applied,omitempty"`
+}
+
+// RunnerUsage describes peak and average CPU and memory of a runner
+type RunnerUsage struct {
+	Pod        string            `json:"pod,omitempty"`
+	Samples    int32             `json:"samples,omitempty"`
+	PeakCPU    resource.Quantity `json:"peakCPU"`
+	AvgCPU     resource.Quantity `json:"avgCPU"`
+	PeakMemory resource.Quantity `json:"peakMemory"`
+	AvgMemory  resource.Quantity `json:"avgMemory"`
+}
+
+// AlertRecord describes an alert that caused an action on the test run
+type AlertRecord struct {
+	// Name is the `alertname` label of the alert.
+	Name        string            `json:"name,omitempty"`
+	Fingerprint string            `json:"fingerprint"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Action      AlertActionType   `json:"action"`
+	FiredAt     metav1.Time       `json:"firedAt"`
+	ResolvedAt  *metav1.Time      `json:"resolvedAt,omitempty"`
+}
+
+// RunnerErrors describes `level=error` entries in logs of the runners.
+type RunnerErrors struct {
+	// Runners lists the number of errors of each runner that logged any.
+	Runners []RunnerErrorCount `json:"runners,omitempty"`
+	// Messages are the first distinct error messages across all runners.
+	Messages []string `json:"messages,omitempty"`
+}
+
+// RunnerErrorCount is the number of errors logged by a runner.
+type RunnerErrorCount struct {
+	Name  string `json:"name"`
+	Count int32  `json:"count"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Stage",type="string",JSONPath=".status.stage",description="Stage"
@@ -200,7 +512,20 @@ func (k6 TestRunSpec) ParseScript() (*types.Script, error) {
 		return s, nil
 	}
 
-	return nil, errors.New("Script definition should contain one of: ConfigMap, VolumeClaim, LocalFile")
+	// Generated script is stored in a ConfigMap owned by the TestRun;
+	// its name is set when the jobs are built.
+	if conversion := spec.Conversion(); conversion != nil {
+		if conversion.ConfigMap.Name == "" {
+			return nil, errors.New("Script conversion should contain the name of a ConfigMap")
+		}
+		if conversion.ConfigMap.Namespace != "" {
+			return nil, errors.New("ConfigMap of a script conversion should be in the namespace of the TestRun")
+		}
+		s.Type = "ConfigMap"
+		return s, nil
+	}
+
+	return nil, errors.New("Script definition should contain one of: ConfigMap, VolumeClaim, LocalFile, HAR, OpenAPI")
 }
 
 // TestRunI implementation for TestRun
@@ -227,6 +552,35 @@ func (k6 *TestRun) TestRunID() string {
 	return k6.GetStatus().TestRunID
 }
 
+// CrossNamespaceScript tells whether the script is a ConfigMap in another namespace.
+// Such a script is copied to a ConfigMap in the namespace of the TestRun.
+func (k6 *TestRun) CrossNamespaceScript() bool {
+	ns := k6.GetSpec().Script.ConfigMap.Namespace
+	return len(k6.GetSpec().Script.ConfigMap.Name) > 0 && len(ns) > 0 && ns != k6.Namespace
+}
+
+// Conversion returns the source the script is generated from, if any.
+func (s K6Script) Conversion() *K6ScriptConversion {
+	if s.HAR != nil {
+		return s.HAR
+	}
+	return s.OpenAPI
+}
+
+// GeneratedScript tells whether the script is generated by the initializer
+// from a HAR recording or an OpenAPI spec.
+func (k6 *TestRun) GeneratedScript() bool {
+	return k6.GetSpec().Script.Conversion() != nil
+}
+
+// MaxStartSkewDuration returns spec.maxStartSkew or its default.
+func (k6 *TestRun) MaxStartSkewDuration() time.Duration {
+	if k6.GetSpec().MaxStartSkew == nil {
+		return defaultMaxStartSkew
+	}
+	return k6.GetSpec().MaxStartSkew.Duration
+}
+
 func (k6 *TestRun) ListOptions() *client.ListOptions {
 	selector := labels.SelectorFromSet(map[string]string{
 		"app":    "k6",
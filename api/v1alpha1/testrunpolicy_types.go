@@ -0,0 +1,183 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/netip"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestRunPolicySpec defines what TestRuns in the namespace of the
+// TestRunPolicy may be granted
+type TestRunPolicySpec struct {
+	// Permissions lists the rules that may be requested in
+	// spec.runner.permissions of TestRuns. A requested rule is allowed
+	// if each of its verbs, resources and names is covered by these rules.
+	Permissions []rbacv1.PolicyRule `json:"permissions,omitempty"`
+	// Targets lists the targets that may be declared in spec.targets of
+	// TestRuns. If any TestRunPolicy of the namespace lists targets, egress
+	// of all runners in the namespace is restricted to their spec.targets
+	// and outputs, and each of them must be covered by these targets.
+	Targets []Target `json:"targets,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TestRunPolicy is the Schema for the testrunpolicies API.
+// It is an allowlist for TestRuns of its namespace, set up by
+// administrators of the namespace.
+type TestRunPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TestRunPolicySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TestRunPolicyList contains a list of TestRunPolicy
+type TestRunPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TestRunPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TestRunPolicy{}, &TestRunPolicyList{})
+}
+
+// AllowsRule tells whether the policy allows runners to be granted the rule.
+// Rules for non-resource URLs are never allowed as they can't be granted
+// by a namespaced Role.
+func (p *TestRunPolicy) AllowsRule(rule rbacv1.PolicyRule) bool {
+	if len(rule.NonResourceURLs) > 0 {
+		return false
+	}
+
+	// a rule without resource names applies to all of them
+	names := rule.ResourceNames
+	if len(names) == 0 {
+		names = []string{""}
+	}
+
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			for _, verb := range rule.Verbs {
+				for _, name := range names {
+					if !p.allows(group, resource, verb, name) {
+						return false
+					}
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+func (p *TestRunPolicy) allows(group, resource, verb, name string) bool {
+	for _, allowed := range p.Spec.Permissions {
+		if covers(allowed.APIGroups, group) &&
+			covers(allowed.Resources, resource) &&
+			covers(allowed.Verbs, verb) &&
+			(len(allowed.ResourceNames) == 0 || (len(name) > 0 && contains(allowed.ResourceNames, name))) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsTarget tells whether the policy allows runners to send traffic
+// to the target. A host is covered by the same host or by a `*.` wildcard
+// of its domain, and a CIDR by a CIDR containing it.
+func (p *TestRunPolicy) AllowsTarget(target Target) bool {
+	for _, allowed := range p.Spec.Targets {
+		if !coversPorts(allowed.Ports, target.Ports) {
+			continue
+		}
+		if len(target.Host) > 0 && coversHost(allowed.Host, target.Host) {
+			return true
+		}
+		if len(target.CIDR) > 0 && coversCIDR(allowed.CIDR, target.CIDR) {
+			return true
+		}
+	}
+	return false
+}
+
+func coversPorts(allowed, ports []int32) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	if len(ports) == 0 {
+		return false
+	}
+	for _, port := range ports {
+		found := false
+		for _, a := range allowed {
+			if a == port {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func coversHost(allowed, host string) bool {
+	if len(allowed) == 0 {
+		return false
+	}
+	allowed, host = strings.ToLower(allowed), strings.ToLower(host)
+	if domain, ok := strings.CutPrefix(allowed, "*."); ok {
+		return strings.HasSuffix(host, "."+domain)
+	}
+	return allowed == host
+}
+
+func coversCIDR(allowed, cidr string) bool {
+	a, err := netip.ParsePrefix(allowed)
+	if err != nil {
+		return false
+	}
+	c, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return false
+	}
+	return a.Bits() <= c.Bits() && a.Contains(c.Addr())
+}
+
+// covers tells whether the value is one of the allowed values, which
+// may contain the wildcard `*`. A wildcard is covered only by a wildcard.
+func covers(allowed []string, value string) bool {
+	return contains(allowed, rbacv1.ResourceAll) || contains(allowed, value)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
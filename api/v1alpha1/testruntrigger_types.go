@@ -0,0 +1,198 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// TriggerLabel is set on TestRuns created by a TestRunTrigger, to the name of the trigger.
+	TriggerLabel = "k6.io/trigger"
+	// ScriptRevisionAnnotation is set on TestRuns created by a TestRunTrigger,
+	// to the revision of the script that triggered the TestRun.
+	ScriptRevisionAnnotation = "k6.io/script-revision"
+	// RolloutRevisionAnnotation is set on TestRuns created by a TestRunTrigger
+	// with rollout, to the revision of the workload that triggered the TestRun.
+	RolloutRevisionAnnotation = "k6.io/rollout-revision"
+	// AnalysisLabel is set on TestRuns created by the analysis endpoint.
+	AnalysisLabel = "k6.io/analysis"
+	// AnalysisRunAnnotation is set on TestRuns created by the analysis endpoint,
+	// to the identifier of the analysis run given by the caller.
+	AnalysisRunAnnotation = "k6.io/analysis-run"
+
+	defaultTriggerDebounce = 30 * time.Second
+)
+
+// ConcurrencyPolicy describes what happens when a TestRun is to be created
+// while a previous TestRun of the same trigger is still running.
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent creates the TestRun right away.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent creates the TestRun once the running ones have finished.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes the running TestRuns and creates the new one.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// RolloutChange describes what is considered a new rollout of a workload.
+// +kubebuilder:validation:Enum=Image;Generation
+type RolloutChange string
+
+const (
+	// ImageChange is a change of the image of the container.
+	ImageChange RolloutChange = "Image"
+	// GenerationChange is any change of the spec of the workload.
+	GenerationChange RolloutChange = "Generation"
+)
+
+// TestRunTriggerSpec defines when TestRuns are created and what they look like
+type TestRunTriggerSpec struct {
+	// Template of the TestRuns to create. Unless rollout is set, its script
+	// must be a ConfigMap: a TestRun is created whenever the content of
+	// the ConfigMap changes.
+	Template TestRunTemplate `json:"template"`
+	// Rollout makes the trigger watch a workload instead of the script:
+	// a TestRun is created once a new rollout of the workload completes.
+	Rollout *RolloutTrigger `json:"rollout,omitempty"`
+	// Debounce is how long the script or the workload must stay unchanged
+	// before a TestRun is created. Defaults to 30s.
+	Debounce *metav1.Duration `json:"debounce,omitempty"`
+	// ConcurrencyPolicy tells what to do with TestRuns of the trigger that are
+	// still running when a new one is to be created. Defaults to Forbid.
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// Suspend stops creation of TestRuns on changes. The template can still
+	// be used by the analysis endpoint.
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// RolloutTrigger describes the workload whose rollouts trigger TestRuns
+type RolloutTrigger struct {
+	// Kind of the workload in the namespace of the trigger.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Container whose image identifies the rollout. Defaults to the first container.
+	Container string `json:"container,omitempty"`
+	// ChangeOn tells what is considered a new rollout. Defaults to Image.
+	ChangeOn RolloutChange `json:"changeOn,omitempty"`
+	// InjectImage passes the image of the container to the runners as
+	// K6_ROLLOUT_IMAGE and K6_ROLLOUT_IMAGE_TAG environment variables
+	// and as `rollout_image_tag` tag of the metrics.
+	InjectImage bool `json:"injectImage,omitempty"`
+}
+
+// TestRunTemplate describes the TestRuns created by a trigger
+type TestRunTemplate struct {
+	Metadata PodMetadata `json:"metadata,omitempty"`
+	Spec     TestRunSpec `json:"spec"`
+}
+
+// TestRunTriggerStatus defines the observed state of TestRunTrigger
+type TestRunTriggerStatus struct {
+	// ObservedRevision is the latest seen revision of the script or the workload.
+	ObservedRevision string `json:"observedRevision,omitempty"`
+	// ObservedTime is when ObservedRevision was seen first.
+	ObservedTime *metav1.Time `json:"observedTime,omitempty"`
+	// TriggeredRevision is the revision that was tested last.
+	// On creation of the trigger, it is the current revision.
+	TriggeredRevision string `json:"triggeredRevision,omitempty"`
+	// LastTestRun is the name of the last TestRun created by the trigger.
+	LastTestRun string `json:"lastTestRun,omitempty"`
+	// LastTriggerTime is when the last TestRun was created.
+	LastTriggerTime *metav1.Time `json:"lastTriggerTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Revision",type="string",JSONPath=".status.triggeredRevision"
+//+kubebuilder:printcolumn:name="LastTestRun",type="string",JSONPath=".status.lastTestRun"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// TestRunTrigger is the Schema for the testruntriggers API.
+// It creates a TestRun from the template each time the script changes
+// or a rollout of the workload completes.
+type TestRunTrigger struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TestRunTriggerSpec   `json:"spec,omitempty"`
+	Status TestRunTriggerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TestRunTriggerList contains a list of TestRunTrigger
+type TestRunTriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TestRunTrigger `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TestRunTrigger{}, &TestRunTriggerList{})
+}
+
+// Workload returns the workload watched by the trigger.
+// It returns false if the trigger watches the script.
+func (t *TestRunTrigger) Workload() (k8stypes.NamespacedName, bool) {
+	if t.Spec.Rollout == nil {
+		return k8stypes.NamespacedName{}, false
+	}
+	return k8stypes.NamespacedName{Namespace: t.Namespace, Name: t.Spec.Rollout.Name}, true
+}
+
+// Script returns the ConfigMap with the script of the trigger, or with the
+// source the script is generated from. It returns false if the script of
+// the template is not in a ConfigMap.
+func (t *TestRunTrigger) Script() (k8stypes.NamespacedName, bool) {
+	cm := t.Spec.Template.Spec.Script.ConfigMap
+	if conversion := t.Spec.Template.Spec.Script.Conversion(); conversion != nil {
+		cm = conversion.ConfigMap
+	}
+	if len(cm.Name) == 0 {
+		return k8stypes.NamespacedName{}, false
+	}
+
+	namespace := cm.Namespace
+	if len(namespace) == 0 {
+		namespace = t.Namespace
+	}
+	return k8stypes.NamespacedName{Namespace: namespace, Name: cm.Name}, true
+}
+
+// DebounceDuration returns spec.debounce or its default.
+func (t *TestRunTrigger) DebounceDuration() time.Duration {
+	if t.Spec.Debounce == nil {
+		return defaultTriggerDebounce
+	}
+	return t.Spec.Debounce.Duration
+}
+
+// Policy returns spec.concurrencyPolicy or its default.
+func (t *TestRunTrigger) Policy() ConcurrencyPolicy {
+	if len(t.Spec.ConcurrencyPolicy) == 0 {
+		return ForbidConcurrent
+	}
+	return t.Spec.ConcurrencyPolicy
+}
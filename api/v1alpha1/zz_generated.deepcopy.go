@@ -22,10 +22,89 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertAction) DeepCopyInto(out *AlertAction) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertAction.
+func (in *AlertAction) DeepCopy() *AlertAction {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertRecord) DeepCopyInto(out *AlertRecord) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.FiredAt.DeepCopyInto(&out.FiredAt)
+	if in.ResolvedAt != nil {
+		in, out := &in.ResolvedAt, &out.ResolvedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertRecord.
+func (in *AlertRecord) DeepCopy() *AlertRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Artifact) DeepCopyInto(out *Artifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Artifact.
+func (in *Artifact) DeepCopy() *Artifact {
+	if in == nil {
+		return nil
+	}
+	out := new(Artifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GangScheduling) DeepCopyInto(out *GangScheduling) {
+	*out = *in
+	if in.ScheduleTimeoutSeconds != nil {
+		in, out := &in.ScheduleTimeoutSeconds, &out.ScheduleTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GangScheduling.
+func (in *GangScheduling) DeepCopy() *GangScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(GangScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InitContainer) DeepCopyInto(out *InitContainer) {
 	*out = *in
@@ -92,6 +171,16 @@ func (in *K6Script) DeepCopyInto(out *K6Script) {
 	*out = *in
 	out.VolumeClaim = in.VolumeClaim
 	out.ConfigMap = in.ConfigMap
+	if in.HAR != nil {
+		in, out := &in.HAR, &out.HAR
+		*out = new(K6ScriptConversion)
+		**out = **in
+	}
+	if in.OpenAPI != nil {
+		in, out := &in.OpenAPI, &out.OpenAPI
+		*out = new(K6ScriptConversion)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K6Script.
@@ -104,6 +193,22 @@ func (in *K6Script) DeepCopy() *K6Script {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *K6ScriptConversion) DeepCopyInto(out *K6ScriptConversion) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K6ScriptConversion.
+func (in *K6ScriptConversion) DeepCopy() *K6ScriptConversion {
+	if in == nil {
+		return nil
+	}
+	out := new(K6ScriptConversion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *K6Scuttle) DeepCopyInto(out *K6Scuttle) {
 	*out = *in
@@ -217,6 +322,13 @@ func (in *Pod) DeepCopyInto(out *Pod) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pod.
@@ -368,26 +480,190 @@ func (in *PrivateLoadZoneStatus) DeepCopy() *PrivateLoadZoneStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TestRun) DeepCopyInto(out *TestRun) {
+func (in *Report) DeepCopyInto(out *Report) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Report.
+func (in *Report) DeepCopy() *Report {
+	if in == nil {
+		return nil
+	}
+	out := new(Report)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceUsage) DeepCopyInto(out *ResourceUsage) {
+	*out = *in
+	if in.LastSampleTime != nil {
+		in, out := &in.LastSampleTime, &out.LastSampleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Runners != nil {
+		in, out := &in.Runners, &out.Runners
+		*out = make([]RunnerUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PerVU != nil {
+		in, out := &in.PerVU, &out.PerVU
+		*out = new(RunnerUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recommended != nil {
+		in, out := &in.Recommended, &out.Recommended
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsage.
+func (in *ResourceUsage) DeepCopy() *ResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RightSizing) DeepCopyInto(out *RightSizing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RightSizing.
+func (in *RightSizing) DeepCopy() *RightSizing {
+	if in == nil {
+		return nil
+	}
+	out := new(RightSizing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutTrigger) DeepCopyInto(out *RolloutTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutTrigger.
+func (in *RolloutTrigger) DeepCopy() *RolloutTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunnerErrorCount) DeepCopyInto(out *RunnerErrorCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunnerErrorCount.
+func (in *RunnerErrorCount) DeepCopy() *RunnerErrorCount {
+	if in == nil {
+		return nil
+	}
+	out := new(RunnerErrorCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunnerErrors) DeepCopyInto(out *RunnerErrors) {
+	*out = *in
+	if in.Runners != nil {
+		in, out := &in.Runners, &out.Runners
+		*out = make([]RunnerErrorCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.Messages != nil {
+		in, out := &in.Messages, &out.Messages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunnerErrors.
+func (in *RunnerErrors) DeepCopy() *RunnerErrors {
+	if in == nil {
+		return nil
+	}
+	out := new(RunnerErrors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunnerReplacement) DeepCopyInto(out *RunnerReplacement) {
+	*out = *in
+	in.LostAt.DeepCopyInto(&out.LostAt)
+	if in.ReplacedAt != nil {
+		in, out := &in.ReplacedAt, &out.ReplacedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunnerReplacement.
+func (in *RunnerReplacement) DeepCopy() *RunnerReplacement {
+	if in == nil {
+		return nil
+	}
+	out := new(RunnerReplacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunnerUsage) DeepCopyInto(out *RunnerUsage) {
+	*out = *in
+	out.PeakCPU = in.PeakCPU.DeepCopy()
+	out.AvgCPU = in.AvgCPU.DeepCopy()
+	out.PeakMemory = in.PeakMemory.DeepCopy()
+	out.AvgMemory = in.AvgMemory.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunnerUsage.
+func (in *RunnerUsage) DeepCopy() *RunnerUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(RunnerUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScriptGrant) DeepCopyInto(out *ScriptGrant) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRun.
-func (in *TestRun) DeepCopy() *TestRun {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptGrant.
+func (in *ScriptGrant) DeepCopy() *ScriptGrant {
 	if in == nil {
 		return nil
 	}
-	out := new(TestRun)
+	out := new(ScriptGrant)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TestRun) DeepCopyObject() runtime.Object {
+func (in *ScriptGrant) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -395,31 +671,46 @@ func (in *TestRun) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TestRunList) DeepCopyInto(out *TestRunList) {
+func (in *ScriptGrantFrom) DeepCopyInto(out *ScriptGrantFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptGrantFrom.
+func (in *ScriptGrantFrom) DeepCopy() *ScriptGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ScriptGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScriptGrantList) DeepCopyInto(out *ScriptGrantList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]TestRun, len(*in))
+		*out = make([]ScriptGrant, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunList.
-func (in *TestRunList) DeepCopy() *TestRunList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptGrantList.
+func (in *ScriptGrantList) DeepCopy() *ScriptGrantList {
 	if in == nil {
 		return nil
 	}
-	out := new(TestRunList)
+	out := new(ScriptGrantList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TestRunList) DeepCopyObject() runtime.Object {
+func (in *ScriptGrantList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -427,52 +718,551 @@ func (in *TestRunList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TestRunSpec) DeepCopyInto(out *TestRunSpec) {
+func (in *ScriptGrantSpec) DeepCopyInto(out *ScriptGrantSpec) {
 	*out = *in
-	out.Script = in.Script
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]v1.ContainerPort, len(*in))
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]ScriptGrantFrom, len(*in))
 		copy(*out, *in)
 	}
-	if in.Initializer != nil {
-		in, out := &in.Initializer, &out.Initializer
-		*out = new(Pod)
-		(*in).DeepCopyInto(*out)
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]ScriptGrantTo, len(*in))
+		copy(*out, *in)
 	}
-	in.Starter.DeepCopyInto(&out.Starter)
-	in.Runner.DeepCopyInto(&out.Runner)
-	out.Scuttle = in.Scuttle
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunSpec.
-func (in *TestRunSpec) DeepCopy() *TestRunSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptGrantSpec.
+func (in *ScriptGrantSpec) DeepCopy() *ScriptGrantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TestRunSpec)
+	out := new(ScriptGrantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TestRunStatus) DeepCopyInto(out *TestRunStatus) {
+func (in *ScriptGrantTo) DeepCopyInto(out *ScriptGrantTo) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptGrantTo.
+func (in *ScriptGrantTo) DeepCopy() *ScriptGrantTo {
+	if in == nil {
+		return nil
+	}
+	out := new(ScriptGrantTo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Spread) DeepCopyInto(out *Spread) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Spread.
+func (in *Spread) DeepCopy() *Spread {
+	if in == nil {
+		return nil
+	}
+	out := new(Spread)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartSkew) DeepCopyInto(out *StartSkew) {
+	*out = *in
+	if in.Runners != nil {
+		in, out := &in.Runners, &out.Runners
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunStatus.
-func (in *TestRunStatus) DeepCopy() *TestRunStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartSkew.
+func (in *StartSkew) DeepCopy() *StartSkew {
 	if in == nil {
 		return nil
 	}
-	out := new(TestRunStatus)
+	out := new(StartSkew)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Target) DeepCopyInto(out *Target) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Target.
+func (in *Target) DeepCopy() *Target {
+	if in == nil {
+		return nil
+	}
+	out := new(Target)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Termination) DeepCopyInto(out *Termination) {
+	*out = *in
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Termination.
+func (in *Termination) DeepCopy() *Termination {
+	if in == nil {
+		return nil
+	}
+	out := new(Termination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRun) DeepCopyInto(out *TestRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRun.
+func (in *TestRun) DeepCopy() *TestRun {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TestRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunList) DeepCopyInto(out *TestRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TestRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunList.
+func (in *TestRunList) DeepCopy() *TestRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TestRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunPolicy) DeepCopyInto(out *TestRunPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunPolicy.
+func (in *TestRunPolicy) DeepCopy() *TestRunPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TestRunPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunPolicyList) DeepCopyInto(out *TestRunPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TestRunPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunPolicyList.
+func (in *TestRunPolicyList) DeepCopy() *TestRunPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TestRunPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunPolicySpec) DeepCopyInto(out *TestRunPolicySpec) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]Target, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunPolicySpec.
+func (in *TestRunPolicySpec) DeepCopy() *TestRunPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunResults) DeepCopyInto(out *TestRunResults) {
+	*out = *in
+	if in.FailedThresholds != nil {
+		in, out := &in.FailedThresholds, &out.FailedThresholds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunResults.
+func (in *TestRunResults) DeepCopy() *TestRunResults {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunResults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunSpec) DeepCopyInto(out *TestRunSpec) {
+	*out = *in
+	in.Script.DeepCopyInto(&out.Script)
+	if in.Spread != nil {
+		in, out := &in.Spread, &out.Spread
+		*out = new(Spread)
+		**out = **in
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]v1.ContainerPort, len(*in))
+		copy(*out, *in)
+	}
+	if in.Initializer != nil {
+		in, out := &in.Initializer, &out.Initializer
+		*out = new(Pod)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Starter.DeepCopyInto(&out.Starter)
+	in.Runner.DeepCopyInto(&out.Runner)
+	out.Scuttle = in.Scuttle
+	out.Report = in.Report
+	if in.AlertActions != nil {
+		in, out := &in.AlertActions, &out.AlertActions
+		*out = make([]AlertAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RightSizing != nil {
+		in, out := &in.RightSizing, &out.RightSizing
+		*out = new(RightSizing)
+		**out = **in
+	}
+	if in.GangScheduling != nil {
+		in, out := &in.GangScheduling, &out.GangScheduling
+		*out = new(GangScheduling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Termination != nil {
+		in, out := &in.Termination, &out.Termination
+		*out = new(Termination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxStartSkew != nil {
+		in, out := &in.MaxStartSkew, &out.MaxStartSkew
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]Target, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunSpec.
+func (in *TestRunSpec) DeepCopy() *TestRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunStatus) DeepCopyInto(out *TestRunStatus) {
+	*out = *in
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]Artifact, len(*in))
+		copy(*out, *in)
+	}
+	if in.RunnerErrors != nil {
+		in, out := &in.RunnerErrors, &out.RunnerErrors
+		*out = new(RunnerErrors)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = new(TestRunResults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = make([]AlertRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = new(ResourceUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartSkew != nil {
+		in, out := &in.StartSkew, &out.StartSkew
+		*out = new(StartSkew)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplacedRunners != nil {
+		in, out := &in.ReplacedRunners, &out.ReplacedRunners
+		*out = make([]RunnerReplacement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunStatus.
+func (in *TestRunStatus) DeepCopy() *TestRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunTemplate) DeepCopyInto(out *TestRunTemplate) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunTemplate.
+func (in *TestRunTemplate) DeepCopy() *TestRunTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunTrigger) DeepCopyInto(out *TestRunTrigger) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunTrigger.
+func (in *TestRunTrigger) DeepCopy() *TestRunTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TestRunTrigger) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunTriggerList) DeepCopyInto(out *TestRunTriggerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TestRunTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunTriggerList.
+func (in *TestRunTriggerList) DeepCopy() *TestRunTriggerList {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunTriggerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TestRunTriggerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunTriggerSpec) DeepCopyInto(out *TestRunTriggerSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutTrigger)
+		**out = **in
+	}
+	if in.Debounce != nil {
+		in, out := &in.Debounce, &out.Debounce
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunTriggerSpec.
+func (in *TestRunTriggerSpec) DeepCopy() *TestRunTriggerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunTriggerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRunTriggerStatus) DeepCopyInto(out *TestRunTriggerStatus) {
+	*out = *in
+	if in.ObservedTime != nil {
+		in, out := &in.ObservedTime, &out.ObservedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastTriggerTime != nil {
+		in, out := &in.LastTriggerTime, &out.LastTriggerTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRunTriggerStatus.
+func (in *TestRunTriggerStatus) DeepCopy() *TestRunTriggerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRunTriggerStatus)
 	in.DeepCopyInto(out)
 	return out
 }
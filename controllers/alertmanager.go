@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	"github.com/grafana/k6-operator/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// AlertmanagerPath is the path of the Alertmanager webhook receiver.
+const AlertmanagerPath = "/alertmanager"
+
+// AlertmanagerHandler receives Alertmanager notifications and applies
+// spec.alertActions of running TestRuns matching the alerts. Alerts are
+// recorded in the status only once the runners have been paused, resumed or
+// stopped, so a failed notification is redone on retry by Alertmanager.
+func (r *TestRunReconciler) AlertmanagerHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !validToken(req, token) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		var msg types.AlertmanagerMessage
+		if err := json.NewDecoder(io.LimitReader(req.Body, maxNotificationSize)).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), pushTimeout)
+		defer cancel()
+
+		list := &v1alpha1.TestRunList{}
+		if err := r.List(ctx, list); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var failed bool
+		for i := range list.Items {
+			k6 := &list.Items[i]
+			if k6.Status.Stage != "started" || len(k6.Spec.AlertActions) == 0 {
+				continue
+			}
+
+			log := r.Log.WithValues("namespace", k6.Namespace, "name", k6.Name)
+			if err := r.applyAlerts(ctx, log, k6, msg.Alerts); err != nil {
+				log.Error(err, "Failed to apply alert actions")
+				failed = true
+			}
+		}
+
+		if failed {
+			http.Error(w, "failed to apply alert actions to some test runs", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// applyAlerts records the alerts matching spec.alertActions of the TestRun
+// and acts on its runners.
+func (r *TestRunReconciler) applyAlerts(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, alerts []types.Alert) error {
+	clean := k6.DeepCopy()
+
+	var (
+		outcome testrun.AlertOutcome
+		changed bool
+		cause   string
+	)
+	for _, alert := range alerts {
+		action, err := testrun.MatchAlertAction(k6, alert)
+		if err != nil {
+			return err
+		}
+		if action == nil {
+			continue
+		}
+
+		o, c := testrun.RecordAlert(k6, action, alert)
+		changed = changed || c
+		// stopping takes precedence, otherwise the last alert decides
+		if o != testrun.AlertNoop && outcome != testrun.AlertStop {
+			outcome = o
+			cause = alert.Labels["alertname"]
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if outcome != testrun.AlertNoop {
+		hostnames, err := r.hostnames(ctx, log, false, k6.ListOptions())
+		if err != nil {
+			return err
+		}
+
+		switch outcome {
+		case testrun.AlertPause:
+			err = r.runners().SetPaused(ctx, hostnames, true)
+			v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.TestRunPaused, metav1.ConditionTrue,
+				fmt.Sprintf("paused by alert %s", cause))
+		case testrun.AlertResume:
+			err = r.runners().SetPaused(ctx, hostnames, false)
+			v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.TestRunPaused, metav1.ConditionFalse,
+				"all alerts are resolved")
+		case testrun.AlertStop:
+			err = r.runners().StopRunners(ctx, hostnames)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s runners: %w", strings.ToLower(string(outcome)), err)
+		}
+
+		log.Info(fmt.Sprintf("Runners received %s from alert %s", outcome, cause))
+	}
+
+	return r.Status().Patch(ctx, k6, client.MergeFrom(clean))
+}
+
+// AlertmanagerServer returns a runnable serving AlertmanagerHandler on the address.
+func (r *TestRunReconciler) AlertmanagerServer(addr, token string) manager.Runnable {
+	mux := http.NewServeMux()
+	mux.Handle(AlertmanagerPath, r.AlertmanagerHandler(token))
+
+	return httpServer(r.Log, "Alertmanager webhook", addr, mux)
+}
@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// AnalysisPath is the prefix of the analysis endpoint:
+	// /analysis/<namespace>/<trigger>.
+	AnalysisPath = "/analysis/"
+
+	maxAnalysisWait      = 30 * time.Minute
+	analysisPollInterval = 5 * time.Second
+)
+
+// flaggerPayload is the part of the body of Flagger webhooks used by the endpoint.
+type flaggerPayload struct {
+	Checksum string            `json:"checksum"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// AnalysisHandler serves verdicts of load tests to progressive delivery tools.
+// Each analysis run, identified by the `run` query parameter or the checksum of
+// a Flagger webhook, gets one TestRun created from the template of the trigger,
+// so repeated calls of the same run poll the same TestRun. With `wait`, the
+// handler blocks until the TestRun finishes or the duration passes.
+func (r *TestRunTriggerReconciler) AnalysisHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Flagger can't set headers of webhooks, so it passes the token in the metadata of the payload
+		var payload flaggerPayload
+		if req.Method == http.MethodPost {
+			if err := json.NewDecoder(io.LimitReader(req.Body, maxNotificationSize)).Decode(&payload); err != nil && err != io.EOF {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		if !validToken(req, token) && !equalToken(payload.Metadata["token"], token) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		namespace, name, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, AnalysisPath), "/")
+		if !ok || len(namespace) == 0 || len(name) == 0 || strings.Contains(name, "/") {
+			http.Error(w, "expected path "+AnalysisPath+"<namespace>/<trigger>", http.StatusNotFound)
+			return
+		}
+
+		query := req.URL.Query()
+
+		run := query.Get("run")
+		if len(run) == 0 {
+			run = payload.Checksum
+		}
+		if len(run) == 0 {
+			http.Error(w, "run is not set", http.StatusBadRequest)
+			return
+		}
+
+		var wait time.Duration
+		if v := query.Get("wait"); len(v) > 0 {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid wait: %v", err), http.StatusBadRequest)
+				return
+			}
+			wait = min(d, maxAnalysisWait)
+		}
+
+		maxRegression := testrun.DefaultMaxRegression
+		if v := query.Get("maxRegression"); len(v) > 0 {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid maxRegression: %v", err), http.StatusBadRequest)
+				return
+			}
+			maxRegression = f
+		}
+
+		metric := query.Get("metric")
+		if len(metric) == 0 {
+			metric = testrun.RegressionMetric
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), wait+pushTimeout)
+		defer cancel()
+
+		log := r.Log.WithValues("namespace", namespace, "name", name, "run", run)
+
+		trigger := &v1alpha1.TestRunTrigger{}
+		if err := r.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: name}, trigger); err != nil {
+			status := http.StatusInternalServerError
+			if errors.IsNotFound(err) {
+				status = http.StatusNotFound
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		k6, err := r.analysisTestRun(ctx, trigger, run)
+		if err != nil {
+			log.Error(err, "Failed to start analysis test run")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		deadline := time.Now().Add(wait)
+		for k6.Status.Stage != "finished" && k6.Status.Stage != "error" && time.Now().Before(deadline) {
+			select {
+			case <-ctx.Done():
+				http.Error(w, ctx.Err().Error(), http.StatusGatewayTimeout)
+				return
+			case <-time.After(min(analysisPollInterval, time.Until(deadline))):
+			}
+
+			// a just created TestRun may not be in the cache yet
+			if err := r.Get(ctx, k8stypes.NamespacedName{Namespace: k6.Namespace, Name: k6.Name}, k6); err != nil && !errors.IsNotFound(err) {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		var baseline *v1alpha1.TestRun
+		if k6.Status.Stage == "finished" {
+			list := &v1alpha1.TestRunList{}
+			if err := r.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels{
+				v1alpha1.TriggerLabel:  trigger.Name,
+				v1alpha1.AnalysisLabel: "true",
+			}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			baseline = testrun.AnalysisBaseline(k6, list.Items)
+		}
+
+		verdict := testrun.NewVerdict(k6, baseline, metric, maxRegression)
+
+		status := http.StatusOK
+		if query.Get("httpStatus") == "true" {
+			// Flagger considers any non-2xx response as a failed check.
+			switch verdict.Phase {
+			case testrun.AnalysisRunning:
+				status = http.StatusServiceUnavailable
+			case testrun.AnalysisFailed:
+				status = http.StatusPreconditionFailed
+			case testrun.AnalysisError:
+				status = http.StatusInternalServerError
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(verdict); err != nil {
+			log.Error(err, "Failed to write analysis verdict")
+		}
+	})
+}
+
+// analysisTestRun returns the TestRun of the analysis run, creating it if needed.
+func (r *TestRunTriggerReconciler) analysisTestRun(ctx context.Context, trigger *v1alpha1.TestRunTrigger, run string) (*v1alpha1.TestRun, error) {
+	k6 := testrun.NewAnalysisTestRun(trigger, run)
+	if err := ctrl.SetControllerReference(trigger, k6, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	err := r.Create(ctx, k6)
+	if err == nil {
+		r.Log.Info("Created analysis test run", "testRun", k6.Name, "run", run)
+		return k6, nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	if err := r.Get(ctx, k8stypes.NamespacedName{Namespace: k6.Namespace, Name: k6.Name}, k6); err != nil {
+		return nil, err
+	}
+	return k6, nil
+}
+
+// AnalysisServer returns a runnable serving AnalysisHandler on the address.
+func (r *TestRunTriggerReconciler) AnalysisServer(addr, token string) manager.Runnable {
+	mux := http.NewServeMux()
+	mux.Handle(AnalysisPath, r.AnalysisHandler(token))
+
+	return httpServer(r.Log, "analysis", addr, mux)
+}
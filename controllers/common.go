@@ -12,20 +12,55 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/grafana/k6-operator/api/v1alpha1"
 	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/grafana/k6-operator/pkg/resources/jobs"
 	"github.com/grafana/k6-operator/pkg/testrun"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 const (
 	errMessageTooLong = "Creation of %s takes too long: your configuration might be off. Check if %v were created successfully."
 )
 
+var (
+	errInitializerFailed = errors.New("initializer job has failed")
+	errNotControlled     = errors.New("already exists and is not controlled by the TestRun")
+)
+
+// createOrUpdateControlled creates the object or, if it already exists,
+// updates it. An existing object that is not controlled by the TestRun is
+// never overwritten: errNotControlled is returned for it instead.
+func (r *TestRunReconciler) createOrUpdateControlled(ctx context.Context, k6 *v1alpha1.TestRun, obj client.Object) error {
+	err := r.Create(ctx, obj)
+	if !k8sErrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing := obj.DeepCopyObject().(client.Object)
+	if err = r.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(existing, k6) {
+		kind := fmt.Sprintf("%T", obj)
+		if gvk, err := apiutil.GVKForObject(obj, r.Scheme); err == nil {
+			kind = gvk.Kind
+		}
+		return fmt.Errorf("%s %s %w", kind, obj.GetName(), errNotControlled)
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return r.Update(ctx, obj)
+}
+
 // It may take some time to retrieve inspect output so indicate with boolean if it's ready
 // and use returnErr only for errors that require a change of behaviour. All other errors
 // should just be logged.
@@ -56,7 +91,10 @@ func inspectTestRun(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 
 	// there should be only 1 initializer pod
 	if podList.Items[0].Status.Phase == corev1.PodFailed {
-		returnErr = errors.New("initalizer job has failed")
+		returnErr = errInitializerFailed
+		if msg := initializerError(&podList.Items[0]); len(msg) > 0 {
+			returnErr = fmt.Errorf("%w: %s", errInitializerFailed, msg)
+		}
 		log.Error(returnErr, "error:")
 		return
 	}
@@ -65,33 +103,10 @@ func inspectTestRun(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 		return
 	}
 
-	// Here we need to get the output of the pod
-	// pods/log is not currently supported by controller-runtime client and it is officially
-	// recommended to use REST client instead:
-	// https://github.com/kubernetes-sigs/controller-runtime/issues/1229
-
-	// TODO: if the below errors repeat several times, it'd be a real error case scenario.
-	// How likely is it? Should we track frequency of these errors here?
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		log.Error(err, "unable to fetch in-cluster REST config")
-		returnErr = err
-		return
-	}
-
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		log.Error(err, "unable to get access to clientset")
-		returnErr = err
-		return
-	}
-	req := clientset.CoreV1().Pods(k6.NamespacedName().Namespace).GetLogs(podList.Items[0].Name, &corev1.PodLogOptions{
-		Container: "k6",
-	})
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
 	defer cancel()
 
-	podLogs, err := req.Stream(ctx)
+	podLogs, err := streamPodLogs(ctx, k6.NamespacedName().Namespace, podList.Items[0].Name, "k6")
 	if err != nil {
 		log.Error(err, "unable to stream logs from the pod")
 		returnErr = err
@@ -116,6 +131,47 @@ func inspectTestRun(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 	return
 }
 
+// initializerError returns errors reported by k6 or by the script generator
+// in the termination message of the initializer pod.
+func initializerError(pod *corev1.Pod) string {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name == jobs.ScriptGeneratorContainer && status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return testrun.InitializerError(status.State.Terminated.Message)
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == "k6" && status.State.Terminated != nil {
+			return testrun.InitializerError(status.State.Terminated.Message)
+		}
+	}
+	return ""
+}
+
+// streamPodLogs returns logs of the container in the pod.
+func streamPodLogs(ctx context.Context, namespace, pod, container string) (io.ReadCloser, error) {
+	// pods/log is not currently supported by controller-runtime client and it is officially
+	// recommended to use REST client instead:
+	// https://github.com/kubernetes-sigs/controller-runtime/issues/1229
+
+	// TODO: if the below errors repeat several times, it'd be a real error case scenario.
+	// How likely is it? Should we track frequency of these errors here?
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch in-cluster REST config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get access to clientset: %w", err)
+	}
+
+	req := clientset.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+	})
+
+	return req.Stream(ctx)
+}
+
 // Similarly to inspectTestRun, there may be some errors during load of token
 // that should be just waited out. But other errors should result in change of
 // behaviour in the caller.
@@ -226,27 +282,27 @@ func (r *TestRunReconciler) hostnames(ctx context.Context, log logr.Logger, abor
 	return hostnames, nil
 }
 
-func runSetup(ctx context.Context, hostnames []string, log logr.Logger) error {
+func runSetup(ctx context.Context, runners testrun.RunnerClient, hostnames []string, log logr.Logger) error {
 	log.Info("Invoking setup() on the first runner")
 
-	setupData, err := testrun.RunSetup(ctx, hostnames[0])
+	setupData, err := runners.RunSetup(ctx, hostnames[0])
 	if err != nil {
 		return err
 	}
 
 	log.Info("Sending setup data to the runners")
 
-	if err = testrun.SetSetupData(ctx, hostnames, setupData); err != nil {
+	if err = runners.SetSetupData(ctx, hostnames, setupData); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func runTeardown(ctx context.Context, hostnames []string, log logr.Logger) {
+func runTeardown(ctx context.Context, runners testrun.RunnerClient, hostnames []string, log logr.Logger) {
 	log.Info("Invoking teardown() on the first responsive runner")
 
-	if err := testrun.RunTeardown(ctx, hostnames); err != nil {
+	if err := runners.RunTeardown(ctx, hostnames); err != nil {
 		log.Error(err, "Failed to invoke teardown()")
 	}
 }
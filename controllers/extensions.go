@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/grafana/k6-operator/pkg/resources/jobs"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// StageHook adds steps to the lifecycle of test runs. Hooks are called
+// with the TestRun as the reconciler sees it and may change its status:
+// such changes are stored together with the changes of the reconciler.
+type StageHook interface {
+	// BeforeStage is called on each reconcile, before the actions of the
+	// current stage. If it returns false, the actions are skipped and the
+	// result and error are returned from the reconcile, e.g. to wait for
+	// an external approval before the runners start.
+	BeforeStage(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun) (proceed bool, res ctrl.Result, err error)
+	// StageChanged is called once a new stage of the TestRun is stored.
+	StageChanged(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, previous v1alpha1.Stage)
+}
+
+func (r *TestRunReconciler) jobs() jobs.Builder {
+	if r.Jobs == nil {
+		return jobs.DefaultBuilder{}
+	}
+	return r.Jobs
+}
+
+func (r *TestRunReconciler) runners() testrun.RunnerClient {
+	if r.Runners == nil {
+		return testrun.RESTClient{}
+	}
+	return r.Runners
+}
+
+// cloudClient returns the client of k6 Cloud, creating it if needed.
+func (r *TestRunReconciler) cloudClient(log logr.Logger, token, host string) cloud.Client {
+	r.cloudMu.RLock()
+	c := r.k6CloudClient
+	r.cloudMu.RUnlock()
+
+	if c == nil {
+		c = r.setCloudClient(log, token, host)
+	}
+	return c
+}
+
+func (r *TestRunReconciler) setCloudClient(log logr.Logger, token, host string) cloud.Client {
+	newClient := r.NewCloudClient
+	if newClient == nil {
+		newClient = cloud.NewAPIClient
+	}
+
+	r.cloudMu.Lock()
+	defer r.cloudMu.Unlock()
+	r.k6CloudClient = newClient(log, token, host)
+	return r.k6CloudClient
+}
+
+// beforeStage runs BeforeStage of the hooks until one of them stops the reconcile.
+func (r *TestRunReconciler) beforeStage(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun) (bool, ctrl.Result, error) {
+	for _, hook := range r.Hooks {
+		if proceed, res, err := hook.BeforeStage(ctx, log, k6); !proceed || err != nil {
+			return false, res, err
+		}
+	}
+	return true, ctrl.Result{}, nil
+}
+
+func (r *TestRunReconciler) stageChanged(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, previous v1alpha1.Stage) {
+	for _, hook := range r.Hooks {
+		hook.StageChanged(ctx, log, k6, previous)
+	}
+}
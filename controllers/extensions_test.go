@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/jobs"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeHook records stage changes and stops reconciles while blocked.
+type fakeHook struct {
+	blocked bool
+	changes []v1alpha1.Stage
+}
+
+func (h *fakeHook) BeforeStage(_ context.Context, _ logr.Logger, _ *v1alpha1.TestRun) (bool, ctrl.Result, error) {
+	if h.blocked {
+		return false, ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	return true, ctrl.Result{}, nil
+}
+
+func (h *fakeHook) StageChanged(_ context.Context, _ logr.Logger, k6 *v1alpha1.TestRun, previous v1alpha1.Stage) {
+	h.changes = append(h.changes, previous, k6.GetStatus().Stage)
+}
+
+// fakeBuilder labels the initializer built by the default builder.
+type fakeBuilder struct {
+	jobs.DefaultBuilder
+	initializers int
+}
+
+func (b *fakeBuilder) NewInitializerJob(k6 *v1alpha1.TestRun, argLine string) (*batchv1.Job, error) {
+	b.initializers++
+
+	job, err := b.DefaultBuilder.NewInitializerJob(k6, argLine)
+	if err != nil {
+		return nil, err
+	}
+	job.Labels["platform"] = "test"
+	return job, nil
+}
+
+// fakeRunners records the runners that were asked to stop.
+type fakeRunners struct {
+	testrun.RESTClient
+	stopped []string
+}
+
+func (c *fakeRunners) StopRunners(_ context.Context, hostnames []string) error {
+	c.stopped = append(c.stopped, hostnames...)
+	return nil
+}
+
+func newFakeReconciler(t *testing.T, objs ...client.Object) *TestRunReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	return &TestRunReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&v1alpha1.TestRun{}).
+			Build(),
+		Log:    logr.Discard(),
+		Scheme: scheme,
+	}
+}
+
+func newExtensionsTestRun() *v1alpha1.TestRun {
+	return &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Parallelism: 1,
+			Script: v1alpha1.K6Script{
+				ConfigMap: v1alpha1.K6Configmap{Name: "test", File: "test.js"},
+			},
+		},
+	}
+}
+
+func TestReconcileWithHookAndBuilder(t *testing.T) {
+	k6 := newExtensionsTestRun()
+	r := newFakeReconciler(t, k6)
+
+	hook := &fakeHook{blocked: true}
+	builder := &fakeBuilder{}
+	r.Hooks = []StageHook{hook}
+	r.Jobs = builder
+
+	req := ctrl.Request{NamespacedName: k6.NamespacedName()}
+
+	res, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, res.RequeueAfter, "result of the blocking hook should be returned")
+	assert.Zero(t, builder.initializers)
+
+	hook.blocked = false
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []v1alpha1.Stage{"", "initialization"}, hook.changes)
+	assert.Equal(t, 1, builder.initializers)
+
+	initializers := &batchv1.JobList{}
+	require.NoError(t, r.List(context.Background(), initializers, client.MatchingLabels{"platform": "test"}))
+	assert.Len(t, initializers.Items, 1, "initializer should be created by the builder")
+}
+
+func TestKillJobsWithRunnerClient(t *testing.T) {
+	k6 := newExtensionsTestRun()
+
+	labels := map[string]string{"app": "k6", "k6_cr": "test", "runner": "true"}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "test-1", Namespace: "test", Labels: labels}}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-service-1", Namespace: "test", Labels: labels},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.0.0.1",
+			Selector:  map[string]string{"job-name": "test-1"},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-abcde", Namespace: "test", Labels: map[string]string{"job-name": "test-1"}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "k6",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	}
+
+	r := newFakeReconciler(t, k6, job, service, pod)
+	runners := &fakeRunners{}
+	r.Runners = runners
+
+	allDeleted, err := KillJobs(context.Background(), r.Log, k6, r)
+	require.NoError(t, err)
+	assert.False(t, allDeleted, "running runner should be given time to stop")
+	assert.Equal(t, []string{"10.0.0.1"}, runners.stopped)
+
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(job), job))
+	_, requested := testrun.StopRequestedAt(job)
+	assert.True(t, requested)
+}
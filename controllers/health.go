@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/grafana/k6-operator/pkg/health"
+)
+
+const (
+	// how many polling intervals the PLZ poller may go without polling before it is considered dead
+	pollerLivenessIntervals = 6
+	// how many polling intervals the PLZ poller may go without a successful poll before the operator is not ready
+	pollerReadinessIntervals = 6
+
+	cloudCheckTTL     = time.Minute
+	cloudCheckTimeout = 5 * time.Second
+)
+
+// HealthChecks returns health checks of the PLZ poller. Both pass when
+// there is no registered PLZ.
+func (r *PrivateLoadZoneReconciler) HealthChecks() []health.Check {
+	pollerHealth := func() (cloud.PollerHealth, bool) {
+		r.pollerMu.RLock()
+		defer r.pollerMu.RUnlock()
+
+		if r.poller == nil {
+			return cloud.PollerHealth{}, false
+		}
+		return r.poller.Health(), true
+	}
+
+	return []health.Check{
+		{
+			Name:     "plz-poller",
+			Liveness: true,
+			Checker: func(_ *http.Request) error {
+				h, ok := pollerHealth()
+				if !ok {
+					return nil
+				}
+				return h.Stalled(pollerLivenessIntervals, time.Now())
+			},
+			Details: func() any {
+				h, ok := pollerHealth()
+				if !ok {
+					return nil
+				}
+				return h
+			},
+		},
+		{
+			Name: "plz-cloud",
+			Checker: func(_ *http.Request) error {
+				h, ok := pollerHealth()
+				if !ok || !h.Polling || h.LastAttempt.IsZero() {
+					return nil
+				}
+				if h.LastSuccess.IsZero() {
+					return fmt.Errorf("PLZ poller has not reached k6 Cloud yet: %s", h.LastError)
+				}
+				if since := time.Since(h.LastSuccess); since > pollerReadinessIntervals*h.Interval {
+					return fmt.Errorf("PLZ poller has not reached k6 Cloud for %s: %s", since.Round(time.Second), h.LastError)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// HealthChecks returns health checks of the k6 Cloud API used by cloud output
+// test runs. The check passes until the first cloud output test run.
+func (r *TestRunReconciler) HealthChecks() []health.Check {
+	return []health.Check{
+		{
+			Name: "cloud",
+			Checker: health.Cached(func(req *http.Request) error {
+				r.cloudMu.RLock()
+				client := r.k6CloudClient
+				r.cloudMu.RUnlock()
+
+				if client == nil {
+					return nil
+				}
+
+				ctx, cancel := context.WithTimeout(req.Context(), cloudCheckTimeout)
+				defer cancel()
+
+				if err := client.Ping(ctx); err != nil {
+					return fmt.Errorf("k6 Cloud API is not reachable: %w", err)
+				}
+				return nil
+			}, cloudCheckTTL),
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// httpServer returns a runnable serving the handler on the address
+// until the manager stops.
+func httpServer(log logr.Logger, name, addr string, handler http.Handler) manager.Runnable {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		errCh := make(chan error, 1)
+		go func() {
+			log.Info("Starting "+name+" server", "addr", addr)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+			close(errCh)
+		}()
+
+		select {
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return server.Shutdown(shutdownCtx)
+		}
+	})
+}
+
+// validToken checks the bearer token of the request. The token must not be
+// empty: an endpoint without a token would accept any request.
+func validToken(req *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return ok && equalToken(got, token)
+}
+
+func equalToken(got, token string) bool {
+	return len(token) > 0 && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
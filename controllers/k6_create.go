@@ -8,7 +8,6 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/grafana/k6-operator/api/v1alpha1"
 	"github.com/grafana/k6-operator/pkg/cloud"
-	"github.com/grafana/k6-operator/pkg/resources/jobs"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -50,12 +49,14 @@ func CreateJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *T
 
 	log.Info("Creating test jobs")
 
+	applyRecommendation(ctx, log, k6, r)
+
 	if res, recheck, err := createJobSpecs(ctx, log, k6, r, token); err != nil {
 		if v1alpha1.IsTrue(k6, v1alpha1.CloudTestRun) {
 			events := cloud.ErrorEvent(cloud.K6OperatorStartError).
 				WithDetail(fmt.Sprintf("Failed to create runner jobs: %v", err)).
 				WithAbort()
-			cloud.SendTestRunEvents(r.k6CloudClient, k6.TestRunID(), log, events)
+			r.k6CloudClient.SendTestRunEvents(k6.TestRunID(), log, events)
 		}
 
 		return res, err
@@ -100,6 +101,10 @@ func createJobSpecs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 		return ctrl.Result{}, false, err
 	}
 
+	if err := createPodGroup(ctx, log, k6, r); err != nil {
+		return ctrl.Result{}, false, err
+	}
+
 	for i := 1; i <= int(k6.GetSpec().Parallelism); i++ {
 		if err := launchTest(ctx, k6, i, log, r, token); err != nil {
 			return ctrl.Result{}, false, err
@@ -116,7 +121,7 @@ func launchTest(ctx context.Context, k6 *v1alpha1.TestRun, index int, log logr.L
 	msg := fmt.Sprintf("Launching k6 test #%d", index)
 	log.Info(msg)
 
-	if job, err = jobs.NewRunnerJob(k6, index, token); err != nil {
+	if job, err = r.jobs().NewRunnerJob(k6, index, token); err != nil {
 		log.Error(err, "Failed to generate k6 test job")
 		return err
 	}
@@ -134,7 +139,7 @@ func launchTest(ctx context.Context, k6 *v1alpha1.TestRun, index int, log logr.L
 		return err
 	}
 
-	if service, err = jobs.NewRunnerService(k6, index); err != nil {
+	if service, err = r.jobs().NewRunnerService(k6, index); err != nil {
 		log.Error(err, "Failed to generate k6 test service")
 		return err
 	}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// startErrorDetail adds diagnostics of the pods of a PLZ test run to the message
+// about a failed start: users of k6 Cloud have no access to the cluster to find
+// out what is off. Diagnostics are collected on a best-effort basis.
+func (r *TestRunReconciler) startErrorDetail(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, msg string) string {
+	if !v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) {
+		return msg
+	}
+
+	name := k6.NamespacedName()
+
+	// initializer, runners and starter
+	pl := &corev1.PodList{}
+	if err := r.List(ctx, pl, client.InNamespace(name.Namespace), client.MatchingLabels{
+		"app":   "k6",
+		"k6_cr": name.Name,
+	}); err != nil {
+		log.Error(err, "Could not list pods for diagnostics")
+		return msg
+	}
+
+	// Events are listed without the cache so that the operator doesn't
+	// have to watch all events in the cluster.
+	ul := &unstructured.UnstructuredList{}
+	ul.SetAPIVersion("v1")
+	ul.SetKind("EventList")
+	if err := r.List(ctx, ul, client.InNamespace(name.Namespace), client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector("type", corev1.EventTypeWarning),
+	}); err != nil {
+		log.Error(err, "Could not list events for diagnostics")
+	}
+
+	var events []corev1.Event
+	for _, u := range ul.Items {
+		var event corev1.Event
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &event); err != nil {
+			continue
+		}
+		// events of the jobs and pods of this test run
+		if strings.HasPrefix(event.InvolvedObject.Name, name.Name+"-") {
+			events = append(events, event)
+		}
+	}
+
+	return testrun.DiagnosticsDetail(msg, testrun.Diagnostics(pl.Items, events))
+}
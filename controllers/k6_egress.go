@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/networkpolicies"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RestrictEgress creates the NetworkPolicy that allows the runners to reach
+// only DNS, spec.targets and the configured outputs. It is owned by the
+// TestRun, so it is deleted together with it, and an existing NetworkPolicy
+// of the same name is updated only if the TestRun controls it. If TestRunPolicies in the
+// namespace of the TestRun list targets, egress is always restricted and
+// all targets must be allowed by them.
+func RestrictEgress(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) (
+	res ctrl.Result, ready bool, err error,
+) {
+	res = ctrl.Result{RequeueAfter: time.Second * 5}
+
+	policies := &v1alpha1.TestRunPolicyList{}
+	if err = r.List(ctx, policies, &client.ListOptions{Namespace: k6.NamespacedName().Namespace}); err != nil {
+		log.Error(err, "Failed to list test run policies")
+		return res, ready, err
+	}
+
+	if !networkpolicies.Enabled(k6, policies.Items) {
+		ready = true
+		return res, ready, nil
+	}
+
+	targets := networkpolicies.Targets(k6)
+
+	if err := networkpolicies.Validate(targets, policies.Items); err != nil {
+		return failEgress(ctx, log, k6, r, err)
+	}
+
+	policy, err := networkpolicies.NewEgressPolicy(k6, targets, func(host string) ([]net.IP, error) {
+		return net.DefaultResolver.LookupIP(ctx, "ip", host)
+	}, func(namespace, name string) (*corev1.Service, error) {
+		service := &corev1.Service{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, service); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return service, nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to build the egress NetworkPolicy of the runners")
+		return res, ready, err
+	}
+
+	if err = ctrl.SetControllerReference(k6, policy, r.Scheme); err != nil {
+		log.Error(err, "Failed to set controller reference for the egress NetworkPolicy")
+		return res, ready, err
+	}
+
+	if err = r.createOrUpdateControlled(ctx, k6, policy); err != nil {
+		if errors.Is(err, errNotControlled) {
+			return failEgress(ctx, log, k6, r, err)
+		}
+		log.Error(err, "Failed to create or update the egress NetworkPolicy of the runners")
+		return res, ready, err
+	}
+
+	log.Info(fmt.Sprintf("Restricted egress of the runners with NetworkPolicy %s", policy.Name))
+	v1alpha1.UpdateCondition(k6, v1alpha1.EgressRestricted, metav1.ConditionTrue)
+
+	ready = true
+	return res, ready, nil
+}
+
+// failEgress moves the TestRun to the error stage with the reason in the EgressRestricted condition.
+func failEgress(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, reason error) (
+	res ctrl.Result, ready bool, err error,
+) {
+	log.Error(reason, "Egress of the runners cannot be restricted to the targets")
+
+	v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.EgressRestricted, metav1.ConditionFalse,
+		fmt.Sprintf("Egress of the runners cannot be restricted to the targets: %v", reason))
+	k6.GetStatus().Stage = "error"
+	if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+		return ctrl.Result{}, ready, err
+	}
+
+	return ctrl.Result{}, ready, nil
+}
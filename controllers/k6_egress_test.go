@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRestrictEgress(t *testing.T) {
+	newTestRun := func(targets ...v1alpha1.Target) *v1alpha1.TestRun {
+		k6 := newExtensionsTestRun()
+		k6.UID = "test-uid"
+		k6.Spec.Targets = targets
+		v1alpha1.Initialize(k6)
+		return k6
+	}
+	target := v1alpha1.Target{CIDR: "10.20.0.0/16", Ports: []int32{443}}
+
+	t.Run("restricted", func(t *testing.T) {
+		k6 := newTestRun(target)
+		r := newFakeReconciler(t, k6)
+
+		_, ready, err := RestrictEgress(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.True(t, ready)
+		assert.True(t, v1alpha1.IsTrue(k6, v1alpha1.EgressRestricted))
+
+		// a retry updates the NetworkPolicy of the TestRun
+		_, ready, err = RestrictEgress(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("disallowed target", func(t *testing.T) {
+		k6 := newTestRun(target)
+		policy := &v1alpha1.TestRunPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging-only", Namespace: "test"},
+			Spec:       v1alpha1.TestRunPolicySpec{Targets: []v1alpha1.Target{{CIDR: "10.30.0.0/16"}}},
+		}
+		r := newFakeReconciler(t, k6, policy)
+
+		_, ready, err := RestrictEgress(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.False(t, ready)
+		assert.Equal(t, v1alpha1.Stage("error"), k6.GetStatus().Stage)
+
+		condition := meta.FindStatusCondition(k6.GetStatus().Conditions, v1alpha1.EgressRestricted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "no TestRunPolicy allows targets 10.20.0.0/16:443")
+	})
+
+	t.Run("PLZ test run", func(t *testing.T) {
+		k6 := newExtensionsTestRun()
+		k6.UID = "test-uid"
+		k6.Spec.TestRunID = "6543"
+		v1alpha1.Initialize(k6)
+		policy := &v1alpha1.TestRunPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging-only", Namespace: "test"},
+			Spec:       v1alpha1.TestRunPolicySpec{Targets: []v1alpha1.Target{{CIDR: "10.30.0.0/16"}}},
+		}
+		r := newFakeReconciler(t, k6, policy)
+
+		_, ready, err := RestrictEgress(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.True(t, ready)
+		assert.True(t, v1alpha1.IsUnknown(k6, v1alpha1.EgressRestricted))
+
+		err = r.Get(context.Background(), client.ObjectKey{Namespace: "test", Name: "test-egress"}, &networkingv1.NetworkPolicy{})
+		assert.True(t, errors.IsNotFound(err), "NetworkPolicy should not be created")
+	})
+
+	t.Run("NetworkPolicy of another owner", func(t *testing.T) {
+		k6 := newTestRun(target)
+		existing := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "test-egress", Namespace: "test"}}
+		r := newFakeReconciler(t, k6, existing)
+
+		_, ready, err := RestrictEgress(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.False(t, ready)
+		assert.Equal(t, v1alpha1.Stage("error"), k6.GetStatus().Stage)
+
+		condition := meta.FindStatusCondition(k6.GetStatus().Conditions, v1alpha1.EgressRestricted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "NetworkPolicy test-egress already exists")
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/grafana/k6-operator/api/v1alpha1"
 	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/grafana/k6-operator/pkg/testrun"
 	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,7 +41,8 @@ func FinishJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *T
 		finished, failed int32
 	)
 	for _, job := range jl.Items {
-		if job.Status.Active != 0 {
+		// lost runners count with their replacements
+		if job.Status.Active != 0 || testrun.Replaced(k6, job.Name) {
 			continue
 		}
 		finished++
@@ -57,7 +59,7 @@ func FinishJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *T
 		events := cloud.ErrorEvent(cloud.K6OperatorRunnerError).
 			WithDetail(msg).
 			WithAbort()
-		cloud.SendTestRunEvents(r.k6CloudClient, k6.TestRunID(), log, events)
+		r.k6CloudClient.SendTestRunEvents(k6.TestRunID(), log, events)
 	}
 
 	if finished < k6.GetSpec().Parallelism {
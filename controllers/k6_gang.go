@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/grafana/k6-operator/pkg/resources/podgroups"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// createPodGroup creates the PodGroup of the runners for gang scheduling.
+// It must exist before the runner pods, or the gang scheduler won't admit them.
+func createPodGroup(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) error {
+	if len(podgroups.Scheduler(k6)) == 0 {
+		return nil
+	}
+
+	pg := podgroups.NewPodGroup(k6)
+
+	if err := ctrl.SetControllerReference(k6, pg, r.Scheme); err != nil {
+		log.Error(err, "Failed to set controller reference for the PodGroup")
+		return err
+	}
+
+	if err := r.Create(ctx, pg); err != nil && !errors.IsAlreadyExists(err) {
+		log.Error(err, "Failed to create the PodGroup; is the gang scheduler installed?")
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Created PodGroup %s for %d runners", pg.GetName(), k6.GetSpec().Parallelism))
+	return nil
+}
+
+// WaitForScheduling checks whether the PodGroup of a TestRun with spec.gangScheduling
+// was scheduled, i.e. whether all runners got nodes at once.
+func WaitForScheduling(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) (scheduled bool, res ctrl.Result, err error) {
+	if len(podgroups.Scheduler(k6)) == 0 || v1alpha1.IsTrue(k6, v1alpha1.RunnersScheduled) {
+		return true, ctrl.Result{}, nil
+	}
+
+	res = ctrl.Result{RequeueAfter: time.Second * 5}
+
+	pg := &unstructured.Unstructured{}
+	pg.SetGroupVersionKind(podgroups.GroupVersionKind(k6))
+	name := types.NamespacedName{Name: podgroups.Name(k6), Namespace: k6.NamespacedName().Namespace}
+	if err = r.Get(ctx, name, pg); err != nil {
+		log.Error(err, "Could not get the PodGroup of the runners")
+		return false, res, nil
+	}
+
+	if reason := podgroups.Failed(k6, pg, time.Now()); len(reason) > 0 {
+		res, err = failScheduling(ctx, log, k6, r, reason)
+		return false, res, err
+	}
+
+	phase, scheduled := podgroups.Phase(k6, pg)
+	msg := fmt.Sprintf("PodGroup %s of %d runners is in phase %s", pg.GetName(), k6.GetSpec().Parallelism, phase)
+	log.Info(msg)
+
+	if !scheduled {
+		v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.RunnersScheduled, metav1.ConditionFalse, msg)
+
+		if _, err = r.UpdateStatus(ctx, k6, log); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		return false, res, nil
+	}
+
+	v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.RunnersScheduled, metav1.ConditionTrue, msg)
+
+	if _, err = r.UpdateStatus(ctx, k6, log); err != nil {
+		return false, ctrl.Result{}, err
+	}
+
+	return true, ctrl.Result{}, nil
+}
+
+// failScheduling moves the TestRun to the error stage when its PodGroup won't
+// be scheduled, instead of waiting for the runners forever.
+func failScheduling(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, reason string) (ctrl.Result, error) {
+	msg := fmt.Sprintf("Runners cannot be scheduled: %s", reason)
+	log.Info(msg)
+
+	if v1alpha1.IsTrue(k6, v1alpha1.CloudTestRun) {
+		events := cloud.ErrorEvent(cloud.K6OperatorStartError).
+			WithDetail(msg).
+			WithPublicDetail(r.startErrorDetail(ctx, log, k6, msg)).
+			WithAbort()
+		r.k6CloudClient.SendTestRunEvents(k6.TestRunID(), log, events)
+	}
+
+	// runners haven't started, so their jobs are deleted without stopping
+	// them first; pending runners would otherwise keep waiting for nodes
+	opts := k6.ListOptions()
+	if err := r.DeleteAllOf(ctx, &batchv1.Job{},
+		client.InNamespace(opts.Namespace),
+		client.MatchingLabelsSelector{Selector: opts.LabelSelector},
+		client.PropagationPolicy(metav1.DeletePropagationBackground),
+	); err != nil {
+		log.Error(err, "Failed to delete runner jobs")
+	}
+
+	v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.RunnersScheduled, metav1.ConditionFalse, msg)
+	k6.GetStatus().Stage = "error"
+	if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
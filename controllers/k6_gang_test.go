@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/podgroups"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestWaitForScheduling(t *testing.T) {
+	timeout := int32(60)
+	newTestRun := func() *v1alpha1.TestRun {
+		k6 := newExtensionsTestRun()
+		k6.Spec.Parallelism = 2
+		k6.Spec.GangScheduling = &v1alpha1.GangScheduling{ScheduleTimeoutSeconds: &timeout}
+		v1alpha1.Initialize(k6)
+		return k6
+	}
+	newPodGroup := func(k6 *v1alpha1.TestRun, phase string, created time.Time) *unstructured.Unstructured {
+		pg := podgroups.NewPodGroup(k6)
+		pg.SetCreationTimestamp(metav1.NewTime(created))
+		require.NoError(t, unstructured.SetNestedField(pg.Object, phase, "status", "phase"))
+		return pg
+	}
+	runnerJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:      "test-1",
+		Namespace: "test",
+		Labels:    map[string]string{"app": "k6", "k6_cr": "test", "runner": "true"},
+	}}
+
+	t.Run("pending", func(t *testing.T) {
+		k6 := newTestRun()
+		r := newFakeReconciler(t, k6, newPodGroup(k6, "Pending", time.Now()))
+
+		scheduled, _, err := WaitForScheduling(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.False(t, scheduled)
+
+		condition := meta.FindStatusCondition(k6.GetStatus().Conditions, v1alpha1.RunnersScheduled)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, "PodGroup test-runners of 2 runners is in phase Pending", condition.Message)
+	})
+
+	t.Run("scheduled", func(t *testing.T) {
+		k6 := newTestRun()
+		r := newFakeReconciler(t, k6, newPodGroup(k6, "Scheduled", time.Now()))
+
+		scheduled, _, err := WaitForScheduling(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.True(t, scheduled)
+		assert.True(t, v1alpha1.IsTrue(k6, v1alpha1.RunnersScheduled))
+	})
+
+	for name, pg := range map[string]func(k6 *v1alpha1.TestRun) *unstructured.Unstructured{
+		"failed": func(k6 *v1alpha1.TestRun) *unstructured.Unstructured {
+			return newPodGroup(k6, "Failed", time.Now())
+		},
+		"timed out": func(k6 *v1alpha1.TestRun) *unstructured.Unstructured {
+			return newPodGroup(k6, "Pending", time.Now().Add(-2*time.Minute))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			k6 := newTestRun()
+			job := runnerJob.DeepCopy()
+			r := newFakeReconciler(t, k6, pg(k6), job)
+
+			scheduled, _, err := WaitForScheduling(context.Background(), r.Log, k6, r)
+			require.NoError(t, err)
+			assert.False(t, scheduled)
+			assert.Equal(t, v1alpha1.Stage("error"), k6.GetStatus().Stage)
+
+			condition := meta.FindStatusCondition(k6.GetStatus().Conditions, v1alpha1.RunnersScheduled)
+			require.NotNil(t, condition)
+			assert.Equal(t, metav1.ConditionFalse, condition.Status)
+			assert.Contains(t, condition.Message, "Runners cannot be scheduled: PodGroup test-runners")
+
+			err = r.Get(context.Background(), client.ObjectKeyFromObject(job), job)
+			assert.True(t, errors.IsNotFound(err), "runner job should be deleted")
+		})
+	}
+}
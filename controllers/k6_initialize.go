@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/grafana/k6-operator/pkg/cloud"
 	"github.com/grafana/k6-operator/pkg/resources/jobs"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -24,7 +26,7 @@ func InitializeJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 	cli := types.ParseCLI(k6.GetSpec().Arguments)
 
 	var initializer *batchv1.Job
-	if initializer, err = jobs.NewInitializerJob(k6, cli.ArchiveArgs); err != nil {
+	if initializer, err = r.jobs().NewInitializerJob(k6, cli.ArchiveArgs); err != nil {
 		return res, err
 	}
 
@@ -54,6 +56,10 @@ func RunValidations(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 
 	inspectOutput, inspectReady, err := inspectTestRun(ctx, log, k6, r.Client)
 	if err != nil {
+		if errors.Is(err, errInitializerFailed) {
+			v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.InitializerFailed, metav1.ConditionTrue, err.Error())
+		}
+
 		// Cloud output test run is not created yet at this point, so sending
 		// events is possible only for PLZ test run.
 		if v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) {
@@ -61,7 +67,11 @@ func RunValidations(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 			events := cloud.ErrorEvent(cloud.K6OperatorStartError).
 				WithDetail(fmt.Sprintf("Failed to inspect the test script: %v", err)).
 				WithAbort()
-			cloud.SendTestRunEvents(r.k6CloudClient, k6.TestRunID(), log, events)
+			r.k6CloudClient.SendTestRunEvents(k6.TestRunID(), log, events)
+
+			if _, updateErr := r.UpdateStatus(ctx, k6, log); updateErr != nil {
+				return ctrl.Result{}, ready, updateErr
+			}
 		} else {
 			// if there is any error, we have to reflect it on the K6 manifest
 			k6.GetStatus().Stage = "error"
@@ -81,6 +91,23 @@ func RunValidations(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 
 	log.Info(fmt.Sprintf("k6 inspect: %+v", inspectOutput))
 
+	if k6.GeneratedScript() {
+		if err := StoreGeneratedScript(ctx, log, k6, r); err != nil {
+			log.Error(err, "Failed to store the generated script")
+
+			if errors.Is(err, errInitializerFailed) {
+				v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.InitializerFailed, metav1.ConditionTrue, err.Error())
+				k6.GetStatus().Stage = "error"
+				if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+					return ctrl.Result{}, ready, err
+				}
+
+				return ctrl.Result{}, ready, nil
+			}
+			return res, ready, nil
+		}
+	}
+
 	if int32(inspectOutput.MaxVUs) < k6.GetSpec().Parallelism {
 		err = fmt.Errorf("number of instances > number of VUs")
 		// TODO maybe change this to a warning and simply set parallelism = maxVUs and proceed with execution?
@@ -100,6 +127,34 @@ func RunValidations(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 		return ctrl.Result{}, ready, nil
 	}
 
+	if k6.GetSpec().ReplaceLostRunners && !v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) && !inspectOutput.DurationBased() {
+		err = fmt.Errorf("replacement of lost runners requires duration-based executors")
+		log.Error(err, "Scenarios of the script must all use constant-vus, ramping-vus, constant-arrival-rate or ramping-arrival-rate executors")
+
+		k6.GetStatus().Stage = "error"
+
+		if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+			return ctrl.Result{}, ready, err
+		}
+
+		return ctrl.Result{}, ready, nil
+	}
+
+	// Unsatisfiable spread doesn't fail the test run, as nodes can be
+	// added by an autoscaler, but runners would be pending otherwise.
+	if k6.GetSpec().Spread != nil || k6.GetSpec().Separate {
+		nodes := &corev1.NodeList{}
+		if err := r.List(ctx, nodes); err != nil {
+			log.Error(err, "Failed to list nodes to verify spread of runners")
+		} else if err := jobs.CheckSpread(k6, nodes.Items); err != nil {
+			log.Info(fmt.Sprintf("Warning: spread of runners cannot be satisfied with current nodes: %v", err))
+			v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.SpreadUnsatisfiable, metav1.ConditionTrue,
+				fmt.Sprintf("Spread of runners cannot be satisfied with current nodes: %v", err))
+		} else {
+			v1alpha1.UpdateCondition(k6, v1alpha1.SpreadUnsatisfiable, metav1.ConditionFalse)
+		}
+	}
+
 	if cli.HasCloudOut {
 		v1alpha1.UpdateCondition(k6, v1alpha1.CloudTestRun, metav1.ConditionTrue)
 
@@ -166,7 +221,7 @@ func SetupCloudTest(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun,
 			inspectOutput.SetTestName(script.Filename)
 		}
 
-		if testRunData, err := cloud.CreateTestRun(inspectOutput, k6.GetSpec().Parallelism, host, token, log); err != nil {
+		if testRunData, err := r.cloudClient(log, token, host).CreateTestRun(inspectOutput, k6.GetSpec().Parallelism); err != nil {
 			log.Error(err, "Failed to create a new cloud test run.")
 			return res, nil
 		} else {
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/permissions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GrantPermissions creates the ServiceAccount, Role and RoleBinding of the runners
+// from spec.runner.permissions. They are owned by the TestRun, so they are deleted
+// together with it. The permissions are granted only if all of them are allowed
+// by TestRunPolicies in the namespace of the TestRun.
+func GrantPermissions(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) (
+	res ctrl.Result, ready bool, err error,
+) {
+	res = ctrl.Result{RequeueAfter: time.Second * 5}
+
+	policies := &v1alpha1.TestRunPolicyList{}
+	if err = r.List(ctx, policies, &client.ListOptions{Namespace: k6.NamespacedName().Namespace}); err != nil {
+		log.Error(err, "Failed to list test run policies")
+		return res, ready, err
+	}
+
+	if disallowed := permissions.Disallowed(k6, policies.Items); len(disallowed) > 0 {
+		rules := make([]string, len(disallowed))
+		for i, rule := range disallowed {
+			rules[i] = permissions.Describe(rule)
+		}
+
+		return failPermissions(ctx, log, k6, r, fmt.Errorf("no TestRunPolicy in namespace %s allows runner permissions: %s",
+			k6.NamespacedName().Namespace, strings.Join(rules, "; ")))
+	}
+
+	for _, obj := range []client.Object{
+		permissions.NewServiceAccount(k6),
+		permissions.NewRole(k6),
+		permissions.NewRoleBinding(k6),
+	} {
+		if err = ctrl.SetControllerReference(k6, obj, r.Scheme); err != nil {
+			log.Error(err, "Failed to set controller reference for runner permissions")
+			return res, ready, err
+		}
+
+		if err = r.createOrUpdateControlled(ctx, k6, obj); err != nil {
+			if errors.Is(err, errNotControlled) {
+				return failPermissions(ctx, log, k6, r, err)
+			}
+			log.Error(err, fmt.Sprintf("Failed to create or update %T of runner permissions", obj))
+			return res, ready, err
+		}
+	}
+
+	log.Info(fmt.Sprintf("Granted permissions to the runners with ServiceAccount %s", permissions.Name(k6)))
+	v1alpha1.UpdateCondition(k6, v1alpha1.PermissionsGranted, metav1.ConditionTrue)
+
+	ready = true
+	return res, ready, nil
+}
+
+// failPermissions moves the TestRun to the error stage with the reason in the PermissionsGranted condition.
+func failPermissions(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, reason error) (
+	res ctrl.Result, ready bool, err error,
+) {
+	log.Error(reason, "Permissions cannot be granted to the runners")
+
+	v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.PermissionsGranted, metav1.ConditionFalse,
+		fmt.Sprintf("Permissions cannot be granted to the runners: %v", reason))
+	k6.GetStatus().Stage = "error"
+	if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+		return ctrl.Result{}, ready, err
+	}
+
+	return ctrl.Result{}, ready, nil
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGrantPermissions(t *testing.T) {
+	rule := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}
+	policy := &v1alpha1.TestRunPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "pods", Namespace: "test"},
+		Spec:       v1alpha1.TestRunPolicySpec{Permissions: []rbacv1.PolicyRule{rule}},
+	}
+
+	newTestRun := func(rules ...rbacv1.PolicyRule) *v1alpha1.TestRun {
+		k6 := newExtensionsTestRun()
+		k6.UID = "test-uid"
+		k6.Spec.Runner.Permissions = rules
+		v1alpha1.Initialize(k6)
+		return k6
+	}
+
+	t.Run("granted", func(t *testing.T) {
+		k6 := newTestRun(rule)
+		r := newFakeReconciler(t, k6, policy)
+
+		_, ready, err := GrantPermissions(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.True(t, ready)
+		assert.True(t, v1alpha1.IsTrue(k6, v1alpha1.PermissionsGranted))
+
+		// a retry updates the objects of the TestRun
+		_, ready, err = GrantPermissions(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("disallowed rule", func(t *testing.T) {
+		k6 := newTestRun(rule, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}})
+		r := newFakeReconciler(t, k6, policy)
+
+		_, ready, err := GrantPermissions(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.False(t, ready)
+		assert.Equal(t, v1alpha1.Stage("error"), k6.GetStatus().Stage)
+
+		condition := meta.FindStatusCondition(k6.GetStatus().Conditions, v1alpha1.PermissionsGranted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "list on secrets")
+	})
+
+	t.Run("ServiceAccount of another owner", func(t *testing.T) {
+		k6 := newTestRun(rule)
+		sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "test-runner", Namespace: "test"}}
+		r := newFakeReconciler(t, k6, policy, sa)
+
+		_, ready, err := GrantPermissions(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.False(t, ready)
+		assert.Equal(t, v1alpha1.Stage("error"), k6.GetStatus().Stage)
+
+		condition := meta.FindStatusCondition(k6.GetStatus().Conditions, v1alpha1.PermissionsGranted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "ServiceAccount test-runner already exists")
+	})
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/jobs"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// WaitForAdmission checks whether the runner jobs of a TestRun with spec.queueName
+// were admitted by Kueue. Until all of them are, the runners must not be started.
+func WaitForAdmission(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) (admitted bool, res ctrl.Result, err error) {
+	queue := k6.GetSpec().QueueName
+	if len(queue) == 0 || v1alpha1.IsTrue(k6, v1alpha1.RunnersAdmitted) {
+		return true, ctrl.Result{}, nil
+	}
+
+	// admission may take as long as the quota is used by other workloads
+	res = ctrl.Result{RequeueAfter: time.Second * 5}
+
+	jl := &batchv1.JobList{}
+	if err = r.List(ctx, jl, k6.ListOptions()); err != nil {
+		log.Error(err, "Could not list runner jobs")
+		return false, res, nil
+	}
+
+	count := jobs.Admitted(jl.Items)
+	msg := fmt.Sprintf("%d/%d runner jobs admitted by queue %s", count, k6.GetSpec().Parallelism, queue)
+	log.Info(msg)
+
+	if count < int(k6.GetSpec().Parallelism) {
+		v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.RunnersAdmitted, metav1.ConditionFalse, msg)
+
+		if _, err = r.UpdateStatus(ctx, k6, log); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		return false, res, nil
+	}
+
+	v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.RunnersAdmitted, metav1.ConditionTrue, msg)
+
+	if _, err = r.UpdateStatus(ctx, k6, log); err != nil {
+		return false, ctrl.Result{}, err
+	}
+
+	return true, ctrl.Result{}, nil
+}
@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReplaceLostRunners launches replacements of the runners lost during the
+// test and unpauses them once they are ready, if spec.replaceLostRunners is
+// set. The Service of a lost runner is pointed to its replacement.
+// Replacements are stopped once all the other runners have finished: they
+// started later and would run past the end of the test otherwise. Runners
+// lost after that aren't replaced, and replacements that didn't start by then
+// or within testrun.ReplacementTimeout are given up on.
+// It returns true while any replacement is yet to start.
+func ReplaceLostRunners(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) (pending bool) {
+	if !k6.GetSpec().ReplaceLostRunners || v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) {
+		return false
+	}
+
+	jl := &batchv1.JobList{}
+	if err := r.List(ctx, jl, k6.ListOptions()); err != nil {
+		log.Error(err, "Could not list jobs")
+		return false
+	}
+
+	// runners of the original jobs that are still executing
+	var running int
+	for _, job := range jl.Items {
+		if job.Status.Active > 0 && !testrun.Replacement(k6, job.Name) {
+			running++
+		}
+	}
+
+	for _, job := range jl.Items {
+		if testrun.Replaced(k6, job.Name) {
+			continue
+		}
+
+		pl := &corev1.PodList{}
+		if err := r.List(ctx, pl, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+			log.Error(err, fmt.Sprintf("Could not list pods of runner job %s", job.Name))
+			continue
+		}
+		if !testrun.Lost(&job, pl.Items) {
+			continue
+		}
+
+		if running == 0 {
+			log.Info(fmt.Sprintf("Runner job %s was lost at the end of the test: not replacing it", job.Name))
+			if err := skipReplacement(ctx, log, k6, r, &job, "all other runners had finished"); err != nil {
+				log.Error(err, fmt.Sprintf("Failed to record lost runner job %s", job.Name))
+			}
+			continue
+		}
+
+		if err := replaceRunner(ctx, log, k6, r, &job); err != nil {
+			log.Error(err, fmt.Sprintf("Failed to replace lost runner job %s", job.Name))
+			return true
+		}
+	}
+
+	if running == 0 {
+		stopReplacements(ctx, log, k6, r, jl.Items)
+	}
+
+	return startReplacements(ctx, log, k6, r, running == 0)
+}
+
+// replaceRunner creates a replacement of the lost runner job with the same
+// execution segment and records it in status.replacedRunners.
+func replaceRunner(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, lost *batchv1.Job) error {
+	index, ok := testrun.RunnerIndex(lost)
+	if !ok {
+		return fmt.Errorf("no runner index in runner job %s", lost.Name)
+	}
+
+	var token string // only for cloud tests
+	if v1alpha1.IsTrue(k6, v1alpha1.CloudTestRun) && v1alpha1.IsTrue(k6, v1alpha1.CloudTestRunCreated) {
+		var (
+			tokenReady bool
+			err        error
+		)
+		token, tokenReady, err = loadToken(ctx, log, r.Client, k6.GetSpec().Token, nil)
+		if err != nil {
+			return err
+		}
+		if !tokenReady {
+			return fmt.Errorf("token is not ready")
+		}
+	}
+
+	name := testrun.ReplacementName(k6, index)
+	log.Info(fmt.Sprintf("Runner job %s was lost: replacing it with %s", lost.Name, name))
+
+	job, err := r.jobs().NewRunnerJob(k6, index, token)
+	if err != nil {
+		return err
+	}
+	job.Name = name
+
+	if err = ctrl.SetControllerReference(k6, job, r.Scheme); err != nil {
+		return err
+	}
+	if err = r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	service := &corev1.Service{}
+	if err = r.Get(ctx, types.NamespacedName{
+		Namespace: k6.NamespacedName().Namespace,
+		Name:      fmt.Sprintf("%s-service-%d", k6.NamespacedName().Name, index),
+	}, service); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(service.DeepCopy())
+	service.Spec.Selector["job-name"] = name
+	if err = r.Patch(ctx, service, patch); err != nil {
+		return err
+	}
+
+	k6.GetStatus().ReplacedRunners = append(k6.GetStatus().ReplacedRunners, v1alpha1.RunnerReplacement{
+		Runner:  index,
+		LostJob: lost.Name,
+		Job:     name,
+		LostAt:  metav1.Now(),
+	})
+
+	_, err = r.UpdateStatus(ctx, k6, log)
+	return err
+}
+
+// skipReplacement records in status.replacedRunners that the lost runner job
+// is not replaced, and why.
+func skipReplacement(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, lost *batchv1.Job, reason string) error {
+	index, ok := testrun.RunnerIndex(lost)
+	if !ok {
+		return fmt.Errorf("no runner index in runner job %s", lost.Name)
+	}
+
+	k6.GetStatus().ReplacedRunners = append(k6.GetStatus().ReplacedRunners, v1alpha1.RunnerReplacement{
+		Runner:  index,
+		LostJob: lost.Name,
+		LostAt:  metav1.Now(),
+		GaveUp:  reason,
+	})
+
+	_, err := r.UpdateStatus(ctx, k6, log)
+	return err
+}
+
+// startReplacements unpauses the replacements that are ready. Replacements
+// are given up on and deleted if the other runners have finished or if they
+// didn't start within testrun.ReplacementTimeout. It returns true while any
+// replacement is yet to start.
+func startReplacements(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, finished bool) (pending bool) {
+	var changed bool
+
+	replacements := k6.GetStatus().ReplacedRunners
+	for i := range replacements {
+		if replacements[i].ReplacedAt != nil || replacements[i].GaveUp != "" {
+			continue
+		}
+
+		var reason string
+		if finished {
+			reason = "all other runners finished before the replacement started"
+		} else if time.Since(replacements[i].LostAt.Time) > testrun.ReplacementTimeout {
+			reason = fmt.Sprintf("the replacement didn't start within %s", testrun.ReplacementTimeout)
+		}
+
+		if reason != "" {
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+				Namespace: k6.NamespacedName().Namespace,
+				Name:      replacements[i].Job,
+			}}
+			if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+				log.Error(err, fmt.Sprintf("Failed to delete replacement runner job %s", replacements[i].Job))
+				pending = true
+				continue
+			}
+
+			replacements[i].GaveUp = reason
+			changed = true
+
+			log.Info(fmt.Sprintf("Gave up on replacement runner job %s: %s", replacements[i].Job, reason))
+			continue
+		}
+
+		service := &corev1.Service{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Namespace: k6.NamespacedName().Namespace,
+			Name:      fmt.Sprintf("%s-service-%d", k6.NamespacedName().Name, replacements[i].Runner),
+		}, service); err != nil || !isServiceReady(log, service) {
+			pending = true
+			continue
+		}
+
+		if err := r.runners().SetPaused(ctx, []string{service.Spec.ClusterIP}, false); err != nil {
+			log.Error(err, fmt.Sprintf("Failed to start replacement runner job %s", replacements[i].Job))
+			pending = true
+			continue
+		}
+
+		now := metav1.Now()
+		replacements[i].ReplacedAt = &now
+		replacements[i].Gap = now.Sub(replacements[i].LostAt.Time).Round(time.Second).String()
+		changed = true
+
+		log.Info(fmt.Sprintf("Started replacement runner job %s, %s after runner job %s was lost",
+			replacements[i].Job, replacements[i].Gap, replacements[i].LostJob))
+	}
+
+	if changed {
+		if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+			log.Error(err, "Failed to store replacements of runners")
+		}
+	}
+
+	return pending
+}
+
+// stopReplacements stops the started replacements that are still executing.
+// The ones yet to start are given up on by startReplacements.
+func stopReplacements(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, jobs []batchv1.Job) {
+	started := make(map[string]bool)
+	for _, replacement := range k6.GetStatus().ReplacedRunners {
+		if replacement.ReplacedAt != nil {
+			started[replacement.Job] = true
+		}
+	}
+
+	var hostnames []string
+	for _, job := range jobs {
+		if job.Status.Active == 0 || !started[job.Name] {
+			continue
+		}
+		index, ok := testrun.RunnerIndex(&job)
+		if !ok {
+			continue
+		}
+
+		service := &corev1.Service{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Namespace: k6.NamespacedName().Namespace,
+			Name:      fmt.Sprintf("%s-service-%d", k6.NamespacedName().Name, index),
+		}, service); err != nil {
+			log.Error(err, fmt.Sprintf("Could not get service of replacement runner job %s", job.Name))
+			continue
+		}
+		hostnames = append(hostnames, service.Spec.ClusterIP)
+	}
+
+	if len(hostnames) == 0 {
+		return
+	}
+
+	log.Info(fmt.Sprintf("Stopping %d replacement runners: all other runners have finished", len(hostnames)))
+	if err := r.runners().StopRunners(ctx, hostnames); err != nil {
+		log.Error(err, "Failed to stop replacement runners")
+	}
+}
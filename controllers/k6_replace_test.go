@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReplaceLostRunnersGivesUp(t *testing.T) {
+	labels := map[string]string{"app": "k6", "k6_cr": "test", "runner": "true"}
+	newRunnerJob := func(name string, index string, active int32) *batchv1.Job {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "test",
+				Labels:      labels,
+				Annotations: map[string]string{testrun.RunnerIndexAnnotation: index},
+			},
+			Status: batchv1.JobStatus{Active: active},
+		}
+		if active == 0 {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+		}
+		return job
+	}
+
+	newTestRun := func(replacements ...v1alpha1.RunnerReplacement) *v1alpha1.TestRun {
+		k6 := newExtensionsTestRun()
+		k6.Spec.Parallelism = 2
+		k6.Spec.ReplaceLostRunners = true
+		v1alpha1.Initialize(k6)
+		k6.Status.ReplacedRunners = replacements
+		return k6
+	}
+
+	t.Run("replacement not started in time", func(t *testing.T) {
+		k6 := newTestRun(v1alpha1.RunnerReplacement{
+			Runner:  1,
+			LostJob: "test-1",
+			Job:     "test-1-replacement-1",
+			LostAt:  metav1.NewTime(time.Now().Add(-testrun.ReplacementTimeout - time.Minute)),
+		})
+		replacement := newRunnerJob("test-1-replacement-1", "1", 1)
+		r := newFakeReconciler(t, k6, newRunnerJob("test-2", "2", 1), replacement)
+
+		assert.False(t, ReplaceLostRunners(context.Background(), r.Log, k6, r))
+		require.Len(t, k6.Status.ReplacedRunners, 1)
+		assert.Contains(t, k6.Status.ReplacedRunners[0].GaveUp, "didn't start within")
+
+		err := r.Get(context.Background(), client.ObjectKeyFromObject(replacement), replacement)
+		assert.True(t, errors.IsNotFound(err), "replacement should be deleted")
+	})
+
+	t.Run("runner lost at the end", func(t *testing.T) {
+		k6 := newTestRun()
+		r := newFakeReconciler(t, k6, newRunnerJob("test-1", "1", 0), newRunnerJob("test-2", "2", 0))
+
+		assert.False(t, ReplaceLostRunners(context.Background(), r.Log, k6, r))
+		require.Len(t, k6.Status.ReplacedRunners, 2)
+		for _, replacement := range k6.Status.ReplacedRunners {
+			assert.Empty(t, replacement.Job)
+			assert.Equal(t, "all other runners had finished", replacement.GaveUp)
+		}
+
+		// lost runners are recorded once
+		assert.False(t, ReplaceLostRunners(context.Background(), r.Log, k6, r))
+		assert.Len(t, k6.Status.ReplacedRunners, 2)
+	})
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/report"
+	"github.com/grafana/k6-operator/pkg/resources/configmaps"
+	"github.com/grafana/k6-operator/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// CollectReport waits for all runners to stop execution, merges their results
+// into the reports configured in spec.report and stores them in a ConfigMap.
+// Runners with reports enabled are started with `--linger` so they are
+// removed once the reports are stored.
+func CollectReport(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) (ctrl.Result, error) {
+	if len(k6.GetStatus().TestRunID) > 0 {
+		log = log.WithValues("testRunId", k6.GetStatus().TestRunID)
+	}
+
+	// Test runs can take a long time and usually they aren't supposed
+	// to be too quick. So check in only periodically.
+	res := ctrl.Result{RequeueAfter: time.Second * 15}
+
+	runningTime, _ := v1alpha1.LastUpdate(k6, v1alpha1.TestRunRunning)
+	if time.Since(runningTime) < time.Second*30 || !StoppedJobs(ctx, log, k6, r) {
+		return res, nil
+	}
+
+	hostnames, err := r.hostnames(ctx, log, false, k6.ListOptions())
+	if err != nil {
+		return res, nil
+	}
+
+	log.Info(fmt.Sprintf("Collecting results from %d runners", len(hostnames)))
+
+	var results []*types.RunnerResults
+	for _, hostname := range hostnames {
+		runnerResults, err := r.runners().GetResults(ctx, hostname)
+		if err != nil {
+			// the runner might have failed: proceed with partial results
+			log.Error(err, fmt.Sprintf("Failed to collect results from runner %s", hostname))
+			continue
+		}
+		results = append(results, runnerResults)
+	}
+
+	// Thresholds are known only from the output of `k6 inspect`.
+	inspectOutput, inspectReady, err := inspectTestRun(ctx, log, k6, r.Client)
+	if err != nil || !inspectReady {
+		log.Info("Thresholds are not available: report will contain only checks")
+	}
+
+	merged := report.Merge(results)
+
+	junit, err := report.NewJUnit(k6.NamespacedName().Name, merged, inspectOutput.Thresholds)
+	if err != nil {
+		log.Error(err, "Failed to generate JUnit report")
+		return res, err
+	}
+
+	cm := configmaps.NewReportConfigMap(k6, map[string]string{
+		report.JUnitKey: string(junit),
+	})
+
+	if err = ctrl.SetControllerReference(k6, cm, r.Scheme); err != nil {
+		log.Error(err, "Failed to set controller reference for the report")
+		return res, err
+	}
+
+	if err = r.createOrUpdateControlled(ctx, k6, cm); err != nil {
+		if errors.Is(err, errNotControlled) {
+			return failReport(ctx, log, k6, r, err)
+		}
+		log.Error(err, "Failed to store the report")
+		return res, err
+	}
+
+	log.Info(fmt.Sprintf("Stored JUnit report in ConfigMap %s", cm.Name))
+
+	k6.GetStatus().Artifacts = append(k6.GetStatus().Artifacts, v1alpha1.Artifact{
+		Name:      "junit",
+		ConfigMap: cm.Name,
+		Key:       report.JUnitKey,
+	})
+	v1alpha1.UpdateCondition(k6, v1alpha1.ReportGenerated, metav1.ConditionTrue)
+
+	summary := report.NewSummary(merged, inspectOutput.Thresholds)
+	k6.GetStatus().Results = &v1alpha1.TestRunResults{
+		ThresholdsPassed: summary.ThresholdsPassed,
+		FailedThresholds: summary.FailedThresholds,
+		Metrics:          make(map[string]string, len(summary.Metrics)),
+	}
+	for key, value := range summary.Metrics {
+		k6.GetStatus().Results.Metrics[key] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	// runners are deleted right after so their logs must be scanned now
+	CollectRunnerErrors(ctx, log, k6, r)
+
+	if _, err = r.UpdateStatus(ctx, k6, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// runners may still be stopping and flushing their outputs: they are
+	// deleted on the next reconciles
+	if allDeleted, err := KillJobs(ctx, log, k6, r); err != nil || !allDeleted {
+		return ctrl.Result{RequeueAfter: time.Second * 2}, err
+	}
+
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// failReport moves the TestRun to the error stage with the reason in the
+// ReportGenerated condition. The runners have stopped already: their jobs
+// are deleted so that they don't linger.
+func failReport(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, reason error) (ctrl.Result, error) {
+	log.Error(reason, "Report cannot be stored")
+
+	if _, err := KillJobs(ctx, log, k6, r); err != nil {
+		log.Error(err, "Failed to delete runner jobs")
+	}
+
+	v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.ReportGenerated, metav1.ConditionFalse,
+		fmt.Sprintf("Report cannot be stored: %v", reason))
+	k6.GetStatus().Stage = "error"
+	if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCollectReportConflict(t *testing.T) {
+	k6 := newExtensionsTestRun()
+	k6.UID = "test-uid"
+	k6.Spec.Report.JUnit = true
+	v1alpha1.Initialize(k6)
+	meta.SetStatusCondition(&k6.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.TestRunRunning,
+		Status:             metav1.ConditionTrue,
+		Reason:             "TestRunRunningTrue",
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+	})
+	k6.Status.Stage = "started"
+
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-report", Namespace: "test"},
+		Data:       map[string]string{"notes.txt": "not a report"},
+	}
+	r := newFakeReconciler(t, k6, existing)
+
+	_, err := CollectReport(context.Background(), r.Log, k6, r)
+	require.NoError(t, err)
+	assert.Equal(t, v1alpha1.Stage("error"), k6.GetStatus().Stage)
+	assert.Empty(t, k6.GetStatus().Artifacts)
+
+	condition := meta.FindStatusCondition(k6.GetStatus().Conditions, v1alpha1.ReportGenerated)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Contains(t, condition.Message, "ConfigMap test-report already exists")
+
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(existing), existing))
+	assert.Equal(t, map[string]string{"notes.txt": "not a report"}, existing.Data, "ConfigMap should not be overwritten")
+	assert.Empty(t, existing.OwnerReferences)
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	"github.com/grafana/k6-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// metrics-server refreshes usage every 15s by default
+const usageSamplingInterval = 15 * time.Second
+
+var podMetricsListGVK = schema.GroupVersionKind{
+	Group:   "metrics.k8s.io",
+	Version: "v1beta1",
+	Kind:    "PodMetricsList",
+}
+
+// SampleResourceUsage adds current usage of the runners from the metrics API
+// to status.resourceUsage, at most once per sampling interval. Failures are
+// only logged, as the metrics API is optional.
+func SampleResourceUsage(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) {
+	if k6.GetSpec().RightSizing == nil {
+		return
+	}
+
+	usage := k6.GetStatus().ResourceUsage
+	if usage != nil && usage.LastSampleTime != nil && time.Since(usage.LastSampleTime.Time) < usageSamplingInterval {
+		return
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsListGVK)
+	if err := r.List(ctx, list, k6.ListOptions()); err != nil {
+		log.Error(err, "Failed to get usage of the runners from the metrics API")
+		return
+	}
+
+	var podMetrics types.PodMetricsList
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.UnstructuredContent(), &podMetrics); err != nil {
+		log.Error(err, "Failed to parse usage of the runners")
+		return
+	}
+	if len(podMetrics.Items) == 0 {
+		return
+	}
+
+	if usage == nil {
+		usage = &v1alpha1.ResourceUsage{Key: usageKey(ctx, log, k6, r)}
+	} else {
+		usage = usage.DeepCopy()
+	}
+	testrun.AddUsageSample(usage, podMetrics.Items, metav1.Now())
+	k6.GetStatus().ResourceUsage = usage
+
+	if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+		log.Error(err, "Failed to store usage of the runners")
+	}
+}
+
+// RecommendResources normalizes the observed usage per VU and stores the
+// recommended resources of the runners in status.resourceUsage.
+func RecommendResources(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) {
+	usage := k6.GetStatus().ResourceUsage
+	if usage == nil || usage.PerVU != nil || len(usage.Runners) == 0 {
+		return
+	}
+
+	inspectOutput, ready, err := inspectTestRun(ctx, log, k6, r.Client)
+	if err != nil || !ready {
+		log.Info("Number of VUs is not available: no resources are recommended")
+		return
+	}
+
+	vus := testrun.VUsPerRunner(inspectOutput.MaxVUs, k6.GetSpec().Parallelism)
+	usage = usage.DeepCopy()
+	usage.PerVU = testrun.UsagePerVU(usage, vus)
+	recommended := testrun.RecommendResources(usage.PerVU, vus)
+	usage.Recommended = &recommended
+	k6.GetStatus().ResourceUsage = usage
+
+	log.Info(fmt.Sprintf("Recommended resources of runners with %d VUs: requests %s, limits %s",
+		vus, formatResources(recommended.Requests), formatResources(recommended.Limits)))
+}
+
+// applyRecommendation sets resources of the runners from the latest
+// recommendation for the same trigger or script. The resources are stored in
+// status.resourceUsage.applied, so that all runner jobs of the TestRun,
+// including replacements, are created with them.
+func applyRecommendation(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) {
+	if k6.GetSpec().RightSizing == nil || !k6.GetSpec().RightSizing.Apply {
+		return
+	}
+	if usage := k6.GetStatus().ResourceUsage; usage != nil && usage.Applied != nil {
+		return
+	}
+
+	key := usageKey(ctx, log, k6, r)
+	if len(key) == 0 {
+		return
+	}
+
+	list := &v1alpha1.TestRunList{}
+	if err := r.List(ctx, list, client.InNamespace(k6.NamespacedName().Namespace)); err != nil {
+		log.Error(err, "Failed to list test runs with recommendations")
+		return
+	}
+
+	source := testrun.LatestRecommendation(key, list.Items)
+	if source == nil {
+		log.Info("No recommendation of resources found for " + key)
+		return
+	}
+
+	inspectOutput, ready, err := inspectTestRun(ctx, log, k6, r.Client)
+	if err != nil || !ready {
+		log.Info("Number of VUs is not available: recommendation is not applied")
+		return
+	}
+
+	vus := testrun.VUsPerRunner(inspectOutput.MaxVUs, k6.GetSpec().Parallelism)
+	recommended := testrun.RecommendResources(source.Status.ResourceUsage.PerVU, vus)
+	applied := testrun.MergeResources(k6.GetSpec().Runner.Resources, recommended)
+
+	usage := k6.GetStatus().ResourceUsage.DeepCopy()
+	if usage == nil {
+		usage = &v1alpha1.ResourceUsage{Key: key}
+	}
+	usage.AppliedFrom = source.Name
+	usage.Applied = &applied
+	k6.GetStatus().ResourceUsage = usage
+
+	if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+		log.Error(err, "Failed to store the applied resources")
+	}
+
+	log.Info(fmt.Sprintf("Applied resources recommended by %s to runners with %d VUs", source.Name, vus))
+}
+
+// usageKey returns the key of the TestRun, hashing the script ConfigMap if needed.
+func usageKey(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) string {
+	var revision string
+	if cm := k6.GetSpec().Script.ConfigMap; len(cm.Name) > 0 {
+		namespace := cm.Namespace
+		if len(namespace) == 0 {
+			namespace = k6.NamespacedName().Namespace
+		}
+
+		script := &corev1.ConfigMap{}
+		if err := r.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: cm.Name}, script); err != nil {
+			log.Error(err, "Failed to get the script to identify recommendations")
+		} else {
+			revision = testrun.ScriptRevision(script)
+		}
+	}
+	return testrun.UsageKey(k6, revision)
+}
+
+func formatResources(list corev1.ResourceList) string {
+	return fmt.Sprintf("cpu=%s memory=%s", list.Cpu(), list.Memory())
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxRunnerErrorMessages is the number of distinct error messages kept in status.
+const maxRunnerErrorMessages = 10
+
+// CollectRunnerErrors scans logs of the runners for error entries and puts
+// a summary of them in status, to be stored with the next status update.
+// It is meant to be called once the runners have finished, but before
+// they are deleted.
+func CollectRunnerErrors(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) {
+	if k6.GetStatus().RunnerErrors != nil {
+		return
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, k6.ListOptions()); err != nil {
+		log.Error(err, "Could not list runner pods to collect errors")
+		return
+	}
+
+	// to have deterministic order in status
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*60)
+	defer cancel()
+
+	runnerErrors := &v1alpha1.RunnerErrors{}
+	for _, pod := range pods.Items {
+		logs, err := streamPodLogs(ctx, pod.Namespace, pod.Name, "k6")
+		if err != nil {
+			log.Error(err, fmt.Sprintf("Unable to get logs of runner %s", pod.Name))
+			continue
+		}
+
+		logErrors, err := testrun.ScanErrors(logs, maxRunnerErrorMessages)
+		_ = logs.Close()
+		if err != nil {
+			log.Error(err, fmt.Sprintf("Unable to read logs of runner %s", pod.Name))
+		}
+
+		if logErrors.Count == 0 {
+			continue
+		}
+
+		name := pod.Name
+		if jobName, ok := pod.Labels["job-name"]; ok {
+			name = jobName
+		}
+
+		runnerErrors.Runners = append(runnerErrors.Runners, v1alpha1.RunnerErrorCount{
+			Name:  name,
+			Count: logErrors.Count,
+		})
+		runnerErrors.Messages = testrun.MergeErrorMessages(runnerErrors.Messages, logErrors.Messages, maxRunnerErrorMessages)
+	}
+
+	if len(runnerErrors.Runners) > 0 {
+		log.Info(fmt.Sprintf("%d runners logged errors", len(runnerErrors.Runners)), "errors", runnerErrors.Messages)
+	}
+
+	k6.GetStatus().RunnerErrors = runnerErrors
+}
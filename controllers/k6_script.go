@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/configmaps"
+	"github.com/grafana/k6-operator/pkg/resources/jobs"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CopyScript copies the script ConfigMap from another namespace into a ConfigMap
+// owned by the TestRun. The copy is allowed only if there is a ScriptGrant
+// in the source namespace for the namespace of the TestRun.
+func CopyScript(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) (
+	res ctrl.Result, ready bool, err error,
+) {
+	res = ctrl.Result{RequeueAfter: time.Second * 5}
+
+	source := types.NamespacedName{
+		Namespace: k6.GetSpec().Script.ConfigMap.Namespace,
+		Name:      k6.GetSpec().Script.ConfigMap.Name,
+	}
+	log = log.WithValues("script", source.String())
+
+	granted, err := isScriptGranted(ctx, r.Client, k6.NamespacedName().Namespace, source)
+	if err != nil {
+		log.Error(err, "Failed to list script grants")
+		return res, ready, err
+	}
+
+	if !granted {
+		return failScript(ctx, log, k6, r, fmt.Errorf("no ScriptGrant in namespace %s allows namespace %s to use ConfigMap %s",
+			source.Namespace, k6.NamespacedName().Namespace, source.Name))
+	}
+
+	sourceCm := &corev1.ConfigMap{}
+	if err = r.Get(ctx, source, sourceCm); err != nil {
+		log.Error(err, "Failed to get the script ConfigMap")
+		return res, ready, err
+	}
+
+	cm := configmaps.NewScriptConfigMap(k6, sourceCm)
+
+	if err = ctrl.SetControllerReference(k6, cm, r.Scheme); err != nil {
+		log.Error(err, "Failed to set controller reference for the script")
+		return res, ready, err
+	}
+
+	if err = r.createOrUpdateControlled(ctx, k6, cm); err != nil {
+		if errors.Is(err, errNotControlled) {
+			return failScript(ctx, log, k6, r, err)
+		}
+		log.Error(err, "Failed to copy the script")
+		return res, ready, err
+	}
+
+	log.Info(fmt.Sprintf("Copied the script into ConfigMap %s", cm.Name))
+	v1alpha1.UpdateCondition(k6, v1alpha1.ScriptGranted, metav1.ConditionTrue)
+
+	ready = true
+	return res, ready, nil
+}
+
+// failScript moves the TestRun to the error stage with the reason in the ScriptGranted condition.
+func failScript(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, reason error) (
+	res ctrl.Result, ready bool, err error,
+) {
+	log.Error(reason, "Script cannot be referenced from another namespace")
+
+	v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.ScriptGranted, metav1.ConditionFalse,
+		fmt.Sprintf("Script cannot be referenced from another namespace: %v", reason))
+	k6.GetStatus().Stage = "error"
+	if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+		return ctrl.Result{}, ready, err
+	}
+
+	return ctrl.Result{}, ready, nil
+}
+
+// isScriptGranted tells whether there is a ScriptGrant allowing the namespace
+// to use the script ConfigMap.
+func isScriptGranted(ctx context.Context, c client.Client, namespace string, source types.NamespacedName) (bool, error) {
+	grants := &v1alpha1.ScriptGrantList{}
+	if err := c.List(ctx, grants, &client.ListOptions{Namespace: source.Namespace}); err != nil {
+		return false, err
+	}
+
+	for i := range grants.Items {
+		if grants.Items[i].Allows(namespace, "ConfigMap", source.Name) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// generatedScriptArtifact is the name of the artifact with the generated script.
+const generatedScriptArtifact = "script"
+
+// StoreGeneratedScript stores the script generated by the initializer into
+// a ConfigMap owned by the TestRun, for the runners, and lists it among
+// the artifacts of the test run. The script is read from the logs of the
+// script generator of the initializer pod and checked against the size
+// the generator reported in its termination message. Errors that retries
+// can't fix wrap errInitializerFailed.
+func StoreGeneratedScript(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) error {
+	for _, artifact := range k6.GetStatus().Artifacts {
+		if artifact.Name == generatedScriptArtifact {
+			return nil
+		}
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(k6.NamespacedName().Namespace), client.MatchingLabels{
+		"app":      "k6",
+		"k6_cr":    k6.NamespacedName().Name,
+		"job-name": fmt.Sprintf("%s-initializer", k6.NamespacedName().Name),
+	}); err != nil {
+		return err
+	}
+	if len(podList.Items) < 1 {
+		return fmt.Errorf("no initializer pod found")
+	}
+
+	size, err := generatedScriptSize(&podList.Items[0])
+	if err != nil {
+		return err
+	}
+
+	logCtx, cancel := context.WithTimeout(ctx, time.Second*60)
+	defer cancel()
+
+	podLogs, err := streamPodLogs(logCtx, k6.NamespacedName().Namespace, podList.Items[0].Name, jobs.ScriptGeneratorContainer)
+	if err != nil {
+		return err
+	}
+	defer podLogs.Close()
+
+	script, err := io.ReadAll(io.LimitReader(podLogs, int64(size)+1))
+	if err != nil {
+		return err
+	}
+	if len(script) != size {
+		// e.g. rotation of container logs by the kubelet
+		return fmt.Errorf("%w: the logs of the %s container have %d bytes instead of the %d bytes of the generated script",
+			errInitializerFailed, jobs.ScriptGeneratorContainer, len(script), size)
+	}
+
+	return storeGeneratedScript(ctx, log, k6, r, string(script))
+}
+
+// generatedScriptSize returns the size of the generated script from the
+// termination message of the script generator. Scripts that don't fit into
+// a ConfigMap are rejected before they are read.
+func generatedScriptSize(pod *corev1.Pod) (int, error) {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != jobs.ScriptGeneratorContainer || status.State.Terminated == nil {
+			continue
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(status.State.Terminated.Message))
+		if err != nil {
+			return 0, fmt.Errorf("%w: the %s container didn't report the size of the generated script; the initializer image must provide wc",
+				errInitializerFailed, jobs.ScriptGeneratorContainer)
+		}
+		if size > configmaps.MaxGeneratedScriptSize {
+			return 0, fmt.Errorf("%w: the generated script of %d bytes exceeds %d bytes, the limit of a ConfigMap",
+				errInitializerFailed, size, configmaps.MaxGeneratedScriptSize)
+		}
+		return size, nil
+	}
+
+	return 0, fmt.Errorf("the %s container of pod %s hasn't terminated", jobs.ScriptGeneratorContainer, pod.Name)
+}
+
+// storeGeneratedScript creates or updates the ConfigMap of the generated
+// script. An existing ConfigMap of the same name is updated only if the
+// TestRun controls it.
+func storeGeneratedScript(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, script string) error {
+	if len(strings.TrimSpace(script)) == 0 {
+		return fmt.Errorf("%w: script generator produced an empty script", errInitializerFailed)
+	}
+
+	cm := configmaps.NewGeneratedScriptConfigMap(k6, script)
+
+	if err := ctrl.SetControllerReference(k6, cm, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.createOrUpdateControlled(ctx, k6, cm); err != nil {
+		if errors.Is(err, errNotControlled) {
+			return fmt.Errorf("%w: generated script cannot be stored: %w", errInitializerFailed, err)
+		}
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Stored the generated script in ConfigMap %s", cm.Name))
+
+	k6.GetStatus().Artifacts = append(k6.GetStatus().Artifacts, v1alpha1.Artifact{
+		Name:      generatedScriptArtifact,
+		ConfigMap: cm.Name,
+		Key:       configmaps.GeneratedScriptKey,
+	})
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCopyScript(t *testing.T) {
+	source := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "library"},
+		Data:       map[string]string{"test.js": "export default function () {}"},
+	}
+	grant := &v1alpha1.ScriptGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "library"},
+		Spec: v1alpha1.ScriptGrantSpec{
+			From: []v1alpha1.ScriptGrantFrom{{Namespace: "test"}},
+			To:   []v1alpha1.ScriptGrantTo{{Kind: "ConfigMap", Name: "checkout"}},
+		},
+	}
+
+	newTestRun := func() *v1alpha1.TestRun {
+		k6 := newExtensionsTestRun()
+		k6.UID = "test-uid"
+		k6.Spec.Script.ConfigMap = v1alpha1.K6Configmap{Name: "checkout", Namespace: "library", File: "test.js"}
+		v1alpha1.Initialize(k6)
+		return k6
+	}
+
+	t.Run("granted", func(t *testing.T) {
+		k6 := newTestRun()
+		r := newFakeReconciler(t, k6, source, grant)
+
+		_, ready, err := CopyScript(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.True(t, ready)
+		assert.True(t, v1alpha1.IsTrue(k6, v1alpha1.ScriptGranted))
+
+		// a retry updates the copy of the TestRun
+		_, ready, err = CopyScript(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("not granted", func(t *testing.T) {
+		k6 := newTestRun()
+		r := newFakeReconciler(t, k6, source)
+
+		_, ready, err := CopyScript(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.False(t, ready)
+		assert.Equal(t, v1alpha1.Stage("error"), k6.GetStatus().Stage)
+
+		condition := meta.FindStatusCondition(k6.GetStatus().Conditions, v1alpha1.ScriptGranted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "no ScriptGrant in namespace library")
+	})
+
+	t.Run("ConfigMap of another owner", func(t *testing.T) {
+		k6 := newTestRun()
+		existing := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-script", Namespace: "test"},
+			Data:       map[string]string{"settings.json": "{}"},
+		}
+		r := newFakeReconciler(t, k6, source, grant, existing)
+
+		_, ready, err := CopyScript(context.Background(), r.Log, k6, r)
+		require.NoError(t, err)
+		assert.False(t, ready)
+		assert.Equal(t, v1alpha1.Stage("error"), k6.GetStatus().Stage)
+
+		condition := meta.FindStatusCondition(k6.GetStatus().Conditions, v1alpha1.ScriptGranted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "ConfigMap test-script already exists")
+
+		require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(existing), existing))
+		assert.Equal(t, map[string]string{"settings.json": "{}"}, existing.Data, "ConfigMap should not be overwritten")
+	})
+}
+
+func TestGeneratedScriptSize(t *testing.T) {
+	newPod := func(message string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-initializer-abc"},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name: jobs.ScriptGeneratorContainer,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{Message: message},
+					},
+				}},
+			},
+		}
+	}
+
+	size, err := generatedScriptSize(newPod("2048\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 2048, size)
+
+	_, err = generatedScriptSize(newPod(strconv.Itoa(2 << 20)))
+	assert.ErrorIs(t, err, errInitializerFailed)
+	assert.Contains(t, err.Error(), "exceeds")
+
+	_, err = generatedScriptSize(newPod(""))
+	assert.ErrorIs(t, err, errInitializerFailed)
+
+	_, err = generatedScriptSize(&corev1.Pod{})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errInitializerFailed, "a running generator is not a failure")
+}
+
+func TestStoreGeneratedScript(t *testing.T) {
+	newTestRun := func() *v1alpha1.TestRun {
+		k6 := newExtensionsTestRun()
+		k6.UID = "test-uid"
+		k6.Spec.Script = v1alpha1.K6Script{
+			HAR: &v1alpha1.K6ScriptConversion{ConfigMap: v1alpha1.K6Configmap{Name: "recordings"}},
+		}
+		v1alpha1.Initialize(k6)
+		return k6
+	}
+	script := "export default function () {}\n"
+
+	t.Run("stored", func(t *testing.T) {
+		k6 := newTestRun()
+		r := newFakeReconciler(t, k6)
+
+		require.NoError(t, storeGeneratedScript(context.Background(), r.Log, k6, r, script))
+		assert.Equal(t, []v1alpha1.Artifact{{Name: "script", ConfigMap: "test-generated-script", Key: "test.js"}},
+			k6.GetStatus().Artifacts)
+
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, r.Get(context.Background(), client.ObjectKey{Namespace: "test", Name: "test-generated-script"}, cm))
+		assert.Equal(t, script, cm.Data["test.js"])
+	})
+
+	t.Run("empty script", func(t *testing.T) {
+		k6 := newTestRun()
+		r := newFakeReconciler(t, k6)
+
+		err := storeGeneratedScript(context.Background(), r.Log, k6, r, "\n")
+		assert.ErrorIs(t, err, errInitializerFailed)
+	})
+
+	t.Run("ConfigMap of another owner", func(t *testing.T) {
+		k6 := newTestRun()
+		existing := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-generated-script", Namespace: "test"},
+			Data:       map[string]string{"test.js": "// kept"},
+		}
+		r := newFakeReconciler(t, k6, existing)
+
+		err := storeGeneratedScript(context.Background(), r.Log, k6, r, script)
+		assert.ErrorIs(t, err, errInitializerFailed)
+		assert.Contains(t, err.Error(), "ConfigMap test-generated-script already exists")
+		assert.Empty(t, k6.GetStatus().Artifacts)
+
+		require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(existing), existing))
+		assert.Equal(t, "// kept", existing.Data["test.js"])
+	})
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/grafana/k6-operator/api/v1alpha1"
 	"github.com/grafana/k6-operator/pkg/cloud"
-	"github.com/grafana/k6-operator/pkg/resources/jobs"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -36,6 +35,14 @@ func StartJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *Te
 		log = log.WithValues("testRunId", k6.GetStatus().TestRunID)
 	}
 
+	if admitted, res, err := WaitForAdmission(ctx, log, k6, r); !admitted {
+		return res, err
+	}
+
+	if scheduled, res, err := WaitForScheduling(ctx, log, k6, r); !scheduled {
+		return res, err
+	}
+
 	log.Info("Waiting for pods to get ready")
 
 	opts := k6.ListOptions()
@@ -61,6 +68,14 @@ func StartJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *Te
 			// this should never happen
 			return res, errors.New("Cannot find condition TestRunRunning")
 		} else {
+			// pods of queued runners are created only after admission
+			// and gang scheduled ones are pending until all fit at once
+			for _, cond := range []string{v1alpha1.RunnersAdmitted, v1alpha1.RunnersScheduled} {
+				if waitedAt, ok := v1alpha1.LastUpdate(k6, cond); ok && waitedAt.After(t) {
+					t = waitedAt
+				}
+			}
+
 			// let's try this approach
 			if time.Since(t).Minutes() > 5 {
 				msg := fmt.Sprintf(errMessageTooLong, "runner pods", "runner jobs and pods")
@@ -69,8 +84,9 @@ func StartJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *Te
 				if v1alpha1.IsTrue(k6, v1alpha1.CloudTestRun) {
 					events := cloud.ErrorEvent(cloud.K6OperatorStartError).
 						WithDetail(msg).
+						WithPublicDetail(r.startErrorDetail(ctx, log, k6, msg)).
 						WithAbort()
-					cloud.SendTestRunEvents(r.k6CloudClient, k6.TestRunID(), log, events)
+					r.k6CloudClient.SendTestRunEvents(k6.TestRunID(), log, events)
 				}
 			}
 		}
@@ -93,14 +109,14 @@ func StartJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *Te
 	// setup
 
 	if v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) {
-		if err := runSetup(ctx, hostnames, log); err != nil {
+		if err := runSetup(ctx, r.runners(), hostnames, log); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
 	// starter
 
-	starter := jobs.NewStarterJob(k6, hostnames)
+	starter := r.jobs().NewStarterJob(k6, hostnames)
 
 	if err = ctrl.SetControllerReference(k6, starter, r.Scheme); err != nil {
 		log.Error(err, "Failed to set controller reference for the start job")
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MeasureStartSkew reads timings of the start requests from the logs of the
+// starter once it has finished and stores when each runner started in
+// status.startSkew. A spread larger than spec.maxStartSkew is reported with
+// the StartSkewExceeded condition. Failures are only logged: the measurement
+// is informative.
+func MeasureStartSkew(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) {
+	if k6.GetStatus().StartSkew != nil {
+		return
+	}
+
+	pl := &corev1.PodList{}
+	if err := r.List(ctx, pl, client.InNamespace(k6.Namespace), client.MatchingLabels{
+		"job-name": fmt.Sprintf("%s-starter", k6.NamespacedName().Name),
+	}); err != nil {
+		log.Error(err, "Could not list starter pods")
+		return
+	}
+
+	var starter *corev1.Pod
+	for i := range pl.Items {
+		if phase := pl.Items[i].Status.Phase; phase == corev1.PodSucceeded || phase == corev1.PodFailed {
+			starter = &pl.Items[i]
+			break
+		}
+	}
+	if starter == nil {
+		return
+	}
+
+	sl := &corev1.ServiceList{}
+	if err := r.List(ctx, sl, k6.ListOptions()); err != nil {
+		log.Error(err, "Could not list services")
+		return
+	}
+	names := make(map[string]string, len(sl.Items))
+	for _, service := range sl.Items {
+		names[service.Spec.ClusterIP] = service.Spec.Selector["job-name"]
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+
+	logs, err := streamPodLogs(ctx, starter.Namespace, starter.Name, "k6-curl")
+	if err != nil {
+		log.Error(err, "Unable to get logs of the starter")
+		return
+	}
+	defer logs.Close()
+
+	offsets, err := testrun.StartOffsets(logs)
+	if err != nil {
+		log.Error(err, "Unable to read logs of the starter")
+	}
+
+	skew, spread := testrun.NewStartSkew(offsets, names)
+	if skew == nil {
+		log.Info("No start timings in the logs of the starter")
+		return
+	}
+	k6.GetStatus().StartSkew = skew
+
+	maxSkew := k6.MaxStartSkewDuration()
+	if spread > maxSkew {
+		msg := fmt.Sprintf("runners started %s apart, more than %s", skew.Spread, maxSkew)
+		log.Info(msg)
+		v1alpha1.UpdateConditionWithMessage(k6, v1alpha1.StartSkewExceeded, metav1.ConditionTrue, msg)
+	} else {
+		log.Info(fmt.Sprintf("Runners started %s apart", skew.Spread))
+		v1alpha1.UpdateCondition(k6, v1alpha1.StartSkewExceeded, metav1.ConditionFalse)
+	}
+
+	if _, err := r.UpdateStatus(ctx, k6, log); err != nil {
+		log.Error(err, "Failed to store start skew of the runners")
+	}
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/grafana/k6-operator/api/v1alpha1"
-	"github.com/grafana/k6-operator/pkg/resources/jobs"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -42,7 +41,7 @@ func StopJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *Tes
 		hostnames = append(hostnames, service.Spec.ClusterIP)
 	}
 
-	stopJob := jobs.NewStopJob(k6, hostnames)
+	stopJob := r.jobs().NewStopJob(k6, hostnames)
 
 	if err = ctrl.SetControllerReference(k6, stopJob, r.Scheme); err != nil {
 		log.Error(err, "Failed to set controller reference for the stop job")
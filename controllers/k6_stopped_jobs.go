@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
 	k6api "go.k6.io/k6/api/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
@@ -90,6 +92,9 @@ func StoppedJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *
 
 // KillJobs retrieves all runner jobs and attempts to delete them
 // with propagation policy so that corresponding pods are deleted as well.
+// Runners that are still alive are first stopped through the REST API and
+// given up to spec.termination.timeoutSeconds to stop and flush their outputs;
+// their jobs are deleted once they are done or the timeout passes.
 // On failure, error is returned.
 // On success, error is nil and allDeleted shows if all retrieved jobs were deleted.
 func KillJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler) (allDeleted bool, err error) {
@@ -113,10 +118,25 @@ func KillJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *Tes
 		return
 	}
 
+	sl := &v1.ServiceList{}
+	if err = r.List(ctx, sl, opts); err != nil {
+		log.Error(err, "Could not list services")
+		return
+	}
+
+	services := make(map[string]*v1.Service, len(sl.Items))
+	for i := range sl.Items {
+		services[sl.Items[i].Spec.Selector["job-name"]] = &sl.Items[i]
+	}
+
 	var deleteCount int
 
 	propagationPolicy := client.PropagationPolicy(metav1.DeletionPropagation(metav1.DeletePropagationBackground))
 	for _, job := range jl.Items {
+		if !stopRunner(ctx, log, k6, r, &job, services[job.Name]) {
+			continue
+		}
+
 		if err = r.Delete(ctx, &job, propagationPolicy); err != nil {
 			log.Error(err, fmt.Sprintf("Failed to delete runner job %s", job.Name))
 			// do we need to retry here?
@@ -126,3 +146,49 @@ func KillJobs(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *Tes
 
 	return deleteCount == len(jl.Items), nil
 }
+
+// stopRunner asks the runner of the job to stop, if it is still alive, and
+// tells if its job can be deleted.
+func stopRunner(ctx context.Context, log logr.Logger, k6 *v1alpha1.TestRun, r *TestRunReconciler, job *batchv1.Job, service *v1.Service) (done bool) {
+	pl := &v1.PodList{}
+	if err := r.List(ctx, pl, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		log.Error(err, fmt.Sprintf("Could not list pods of runner job %s", job.Name))
+		return false
+	}
+
+	if testrun.Terminated(pl.Items) || service == nil {
+		return true
+	}
+
+	// a lingering runner has flushed its outputs once it stops running
+	if testrun.Lingering(job) && !isJobRunning(log, service) {
+		return true
+	}
+
+	requestedAt, requested := testrun.StopRequestedAt(job)
+	if !requested {
+		log.Info(fmt.Sprintf("Stopping runner job %s before deletion", job.Name))
+
+		if err := r.runners().StopRunners(ctx, []string{service.Spec.ClusterIP}); err != nil {
+			// an unreachable runner is deleted once the timeout passes
+			log.Error(err, fmt.Sprintf("Failed to stop runner job %s", job.Name))
+		}
+
+		patch := client.MergeFrom(job.DeepCopy())
+		if job.Annotations == nil {
+			job.Annotations = map[string]string{}
+		}
+		job.Annotations[testrun.StopRequestedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		if err := r.Patch(ctx, job, patch); err != nil {
+			log.Error(err, fmt.Sprintf("Failed to annotate runner job %s", job.Name))
+		}
+		return false
+	}
+
+	if time.Since(requestedAt) >= testrun.TerminationTimeout(k6) {
+		log.Info(fmt.Sprintf("Runner job %s did not stop in time: deleting it", job.Name))
+		return true
+	}
+
+	return false
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -48,6 +49,9 @@ type PrivateLoadZoneReconciler struct {
 	Log    logr.Logger
 	Scheme *runtime.Scheme
 
+	// PollingInterval of k6 Cloud for new test runs; cloud.PollingInterval by default.
+	PollingInterval time.Duration
+
 	// Note: we expect that there's only one PLZ object at a time.
 	// Therefore it is safe to assume that poller should be created only once
 	// and it can simply be part of the Reconciler object.
@@ -56,11 +60,15 @@ type PrivateLoadZoneReconciler struct {
 	// e.g. with a map: PLZ name -> poller.
 	poller *cloud.TestRunPoller
 	token  string // needed for cloud logs
+
+	// guards poller for health checks
+	pollerMu sync.RWMutex
 }
 
 //+kubebuilder:rbac:groups=k6.io,resources=privateloadzones,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k6.io,resources=privateloadzones/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k6.io,resources=privateloadzones/finalizers,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=create
 
 // Reconcile takes a PrivateLoadZone object and takes the appropriate action in the cluster
 func (r *PrivateLoadZoneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -88,7 +96,12 @@ func (r *PrivateLoadZoneReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
 		}
 
-		r.poller = cloud.NewTestRunPoller(cloud.ApiURL(k6CloudHost()), token, plz.Name, logger)
+		interval := r.PollingInterval
+		if interval == 0 {
+			interval = cloud.PollingInterval
+		}
+
+		r.setPoller(cloud.NewTestRunPoller(cloud.ApiURL(k6CloudHost()), token, plz.Name, interval, logger))
 		r.token = token
 	}
 
@@ -126,6 +139,7 @@ func (r *PrivateLoadZoneReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		if !plz.DeletionTimestamp.IsZero() && controllerutil.ContainsFinalizer(plz, plzFinalizer) {
 			// PLZ has been deleted.
 
+			r.poller.SetRegistered(false)
 			r.poller.Stop()
 
 			// Since resource is being deleted, there isn't much to do about
@@ -140,7 +154,7 @@ func (r *PrivateLoadZoneReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 				return ctrl.Result{}, err
 			}
 
-			r.poller = nil
+			r.setPoller(nil)
 
 			// nothing left to do
 			return ctrl.Result{}, nil
@@ -148,6 +162,9 @@ func (r *PrivateLoadZoneReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	if plz.IsTrue(v1alpha1.PLZRegistered) {
+		if r.poller != nil {
+			r.poller.SetRegistered(true)
+		}
 		if r.poller != nil && !r.poller.IsPolling() {
 			r.poller.Start()
 			r.startFactory(plz, r.poller.GetTestRuns())
@@ -158,6 +175,13 @@ func (r *PrivateLoadZoneReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	return ctrl.Result{}, nil
 }
 
+func (r *PrivateLoadZoneReconciler) setPoller(poller *cloud.TestRunPoller) {
+	r.pollerMu.Lock()
+	defer r.pollerMu.Unlock()
+
+	r.poller = poller
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *PrivateLoadZoneReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
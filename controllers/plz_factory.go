@@ -7,6 +7,7 @@ import (
 	"github.com/grafana/k6-operator/api/v1alpha1"
 	"github.com/grafana/k6-operator/pkg/cloud"
 	"github.com/grafana/k6-operator/pkg/testrun"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -41,6 +42,13 @@ func (r *PrivateLoadZoneReconciler) startFactory(plz *v1alpha1.PrivateLoadZone,
 				continue
 			}
 
+			// PLZ test runs are removed once they finish: a test run notified
+			// again, e.g. by a replayed webhook request, must not run twice.
+			if cloud.TestRunStatus(trData.RunStatus).Ended() {
+				logger.Info(fmt.Sprintf("Test run `%s` has already ended.", testRunId))
+				continue
+			}
+
 			k6 = testrun.NewPLZTestRun(plz, r.token, trData, k6CloudHost())
 
 			logger.Info(fmt.Sprintf("PLZ test run has been prepared with image `%s` and `%d` instances",
@@ -52,9 +60,36 @@ func (r *PrivateLoadZoneReconciler) startFactory(plz *v1alpha1.PrivateLoadZone,
 
 			if err := r.Create(context.Background(), k6); err != nil {
 				logger.Error(err, "Failed to create PLZ test run", "testRunId", testRunId)
+				continue
+			}
+
+			// The environment from k6 Cloud may contain secrets so it is stored
+			// in a Secret owned by the test run, once the test run exists.
+			if secret := testrun.NewPLZEnvSecret(k6, trData); secret != nil {
+				if err := r.createEnvSecret(k6, secret); err != nil {
+					logger.Error(err, fmt.Sprintf("Failed to create the environment of PLZ test run: %v", trData.Environment), "testRunId", testRunId)
+
+					// runners can't start without their environment:
+					// remove the test run so that it is created again
+					if err := r.Delete(context.Background(), k6); err != nil {
+						logger.Error(err, "Failed to delete PLZ test run", "testRunId", testRunId)
+					}
+					continue
+				}
 			}
 
 			logger.Info("Created new test run", "testRunId", testRunId)
 		}
 	}()
 }
+
+func (r *PrivateLoadZoneReconciler) createEnvSecret(k6 *v1alpha1.TestRun, secret *corev1.Secret) error {
+	if err := ctrl.SetControllerReference(k6, secret, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.Create(context.Background(), secret); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
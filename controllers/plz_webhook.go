@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/k6-operator/pkg/cloud"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// PLZWebhookPath is the path of the webhook for new PLZ test runs.
+	PLZWebhookPath = "/plz/test-runs"
+
+	maxNotificationSize = 1 << 20
+	pushTimeout         = 10 * time.Second
+)
+
+// WebhookHandler serves notifications about new PLZ test runs pushed by k6 Cloud
+// or a relay. Test runs are passed to the same factory as the polled ones, so
+// a test run both pushed and polled is created only once. Requests must be
+// signed together with a recent timestamp, so that they can't be replayed.
+func (r *PrivateLoadZoneReconciler) WebhookHandler(secret []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(req.Body, maxNotificationSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		n, err := cloud.ParseTestRunNotification(secret, body,
+			req.Header.Get(cloud.TimestampHeader), req.Header.Get(cloud.SignatureHeader), time.Now())
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, cloud.ErrInvalidSignature) || errors.Is(err, cloud.ErrStaleNotification) {
+				status = http.StatusUnauthorized
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		r.pollerMu.RLock()
+		poller := r.poller
+		r.pollerMu.RUnlock()
+
+		if poller == nil || !poller.IsPolling() {
+			http.Error(w, "PLZ is not registered yet", http.StatusServiceUnavailable)
+			return
+		}
+		if poller.PLZName() != n.PLZName {
+			http.Error(w, "unknown PLZ", http.StatusNotFound)
+			return
+		}
+
+		testRunId := strconv.FormatUint(n.TestRunID, 10)
+		logger := r.Log.WithValues("testRunId", testRunId)
+
+		ctx, cancel := context.WithTimeout(req.Context(), pushTimeout)
+		defer cancel()
+
+		if err := poller.Push(ctx, testRunId); err != nil {
+			logger.Error(err, "Failed to pass pushed test run to the factory.")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		logger.Info("Received test run from the webhook.")
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// WebhookServer returns a runnable serving WebhookHandler on the address.
+func (r *PrivateLoadZoneReconciler) WebhookServer(addr string, secret []byte) manager.Runnable {
+	mux := http.NewServeMux()
+	mux.Handle(PLZWebhookPath, r.WebhookHandler(secret))
+
+	return httpServer(r.Log, "PLZ webhook", addr, mux)
+}
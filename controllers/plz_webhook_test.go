@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	secret := []byte("secret")
+
+	poller := cloud.NewTestRunPoller("http://127.0.0.1:1", "token", "my-plz", time.Hour, logr.Discard())
+	poller.Start()
+	defer poller.Stop()
+
+	received := make(chan string, 1)
+	go func() {
+		for testRunId := range poller.GetTestRuns() {
+			received <- testRunId
+		}
+	}()
+
+	handler := (&PrivateLoadZoneReconciler{Log: logr.Discard(), poller: poller}).WebhookHandler(secret)
+
+	newRequest := func(body string, sentAt time.Time, key []byte) *http.Request {
+		timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+		req := httptest.NewRequest(http.MethodPost, PLZWebhookPath, strings.NewReader(body))
+		req.Header.Set(cloud.TimestampHeader, timestamp)
+		req.Header.Set(cloud.SignatureHeader, cloud.Sign(key, timestamp, []byte(body)))
+		return req
+	}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"wrong method", httptest.NewRequest(http.MethodGet, PLZWebhookPath, nil), http.StatusMethodNotAllowed},
+		{"wrong secret", newRequest(`{"plz_name":"my-plz","test_run_id":123}`, time.Now(), []byte("other")), http.StatusUnauthorized},
+		{"replayed", newRequest(`{"plz_name":"my-plz","test_run_id":123}`, time.Now().Add(-time.Hour), secret), http.StatusUnauthorized},
+		{"invalid body", newRequest(`{"plz_name":"my-plz"}`, time.Now(), secret), http.StatusBadRequest},
+		{"unknown PLZ", newRequest(`{"plz_name":"other-plz","test_run_id":123}`, time.Now(), secret), http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, test.req)
+			assert.Equal(t, test.status, rec.Code, rec.Body.String())
+		})
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newRequest(`{"plz_name":"my-plz","test_run_id":123}`, time.Now(), secret))
+		assert.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+		assert.Equal(t, "123", <-received)
+	})
+}
+
+func TestWebhookHandlerNotRegistered(t *testing.T) {
+	secret := []byte("secret")
+	handler := (&PrivateLoadZoneReconciler{Log: logr.Discard()}).WebhookHandler(secret)
+
+	body := `{"plz_name":"my-plz","test_run_id":123}`
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, PLZWebhookPath, strings.NewReader(body))
+	req.Header.Set(cloud.TimestampHeader, timestamp)
+	req.Header.Set(cloud.SignatureHeader, cloud.Sign(secret, timestamp, []byte(body)))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
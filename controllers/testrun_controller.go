@@ -18,15 +18,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
-	"go.k6.io/k6/cloudapi"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/go-logr/logr"
 	"github.com/grafana/k6-operator/api/v1alpha1"
 	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/grafana/k6-operator/pkg/resources/jobs"
+	"github.com/grafana/k6-operator/pkg/resources/permissions"
+	"github.com/grafana/k6-operator/pkg/testrun"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -50,20 +53,45 @@ type TestRunReconciler struct {
 	Log    logr.Logger
 	Scheme *runtime.Scheme
 
+	// Extension points for projects embedding the reconciler.
+	// Defaults of the operator are used for the unset ones.
+
+	// Jobs creates the jobs and services of the test runs.
+	Jobs jobs.Builder
+	// Runners is the client of the REST API of the runners.
+	Runners testrun.RunnerClient
+	// NewCloudClient creates the client of k6 Cloud for cloud test runs.
+	NewCloudClient cloud.NewClientFunc
+	// Hooks are called around stages of the test runs, in order.
+	Hooks []StageHook
+
 	// Note: here we assume that all users of the operator are allowed to use
 	// the same token / cloud client.
-	k6CloudClient *cloudapi.Client
+	k6CloudClient cloud.Client
+	// guards k6CloudClient for health checks
+	cloudMu sync.RWMutex
 }
 
 // Reconcile takes a K6 object and takes the appropriate action in the cluster
 // +kubebuilder:rbac:groups=k6.io,resources=testruns,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=k6.io,resources=testruns/status;testruns/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=k6.io,resources=scriptgrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=k6.io,resources=testrunpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;create;update
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;create;update
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=escalate;bind
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;create;update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods;pods/log,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=list
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups=scheduling.x-k8s.io;scheduling.volcano.sh,resources=podgroups,verbs=get;list;watch;create;delete
 
 func (r *TestRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("namespace", req.Namespace, "name", req.Name, "reconcileID", controller.ReconcileIDFromContext(ctx))
@@ -116,6 +144,10 @@ func (r *TestRunReconciler) reconcile(ctx context.Context, req ctrl.Request, log
 		}
 	}
 
+	if proceed, res, err := r.beforeStage(ctx, log, k6); !proceed || err != nil {
+		return res, err
+	}
+
 	switch k6.GetStatus().Stage {
 	case "":
 		log.Info("Initialize test")
@@ -126,6 +158,22 @@ func (r *TestRunReconciler) reconcile(ctx context.Context, req ctrl.Request, log
 			return ctrl.Result{}, err
 		}
 
+		if k6.CrossNamespaceScript() {
+			if res, ready, err := CopyScript(ctx, log, k6, r); !ready {
+				return res, err
+			}
+		}
+
+		if permissions.Enabled(k6) {
+			if res, ready, err := GrantPermissions(ctx, log, k6, r); !ready {
+				return res, err
+			}
+		}
+
+		if res, ready, err := RestrictEgress(ctx, log, k6, r); !ready {
+			return res, err
+		}
+
 		log.Info("Changing stage of TestRun status to initialization")
 		k6.GetStatus().Stage = "initialization"
 
@@ -152,8 +200,9 @@ func (r *TestRunReconciler) reconcile(ctx context.Context, req ctrl.Request, log
 					if isCloudTestRun(k6) {
 						events := cloud.ErrorEvent(cloud.K6OperatorStartError).
 							WithDetail(msg).
+							WithPublicDetail(r.startErrorDetail(ctx, log, k6, msg)).
 							WithAbort()
-						cloud.SendTestRunEvents(r.k6CloudClient, k6.TestRunID(), log, events)
+						r.k6CloudClient.SendTestRunEvents(k6.TestRunID(), log, events)
 					}
 				}
 			}
@@ -211,6 +260,14 @@ func (r *TestRunReconciler) reconcile(ctx context.Context, req ctrl.Request, log
 			return ctrl.Result{}, nil
 		}
 
+		SampleResourceUsage(ctx, log, k6, r)
+		MeasureStartSkew(ctx, log, k6, r)
+
+		// a replacement of a lost runner isn't executing yet
+		if ReplaceLostRunners(ctx, log, k6, r) {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+		}
+
 		if v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) {
 			runningTime, _ := v1alpha1.LastUpdate(k6, v1alpha1.TestRunRunning)
 
@@ -227,7 +284,7 @@ func (r *TestRunReconciler) reconcile(ctx context.Context, req ctrl.Request, log
 					if err != nil {
 						return ctrl.Result{}, nil
 					}
-					runTeardown(ctx, hostnames, log)
+					runTeardown(ctx, r.runners(), hostnames, log)
 					v1alpha1.UpdateCondition(k6, v1alpha1.TeardownExecuted, metav1.ConditionTrue)
 
 					_, err = r.UpdateStatus(ctx, k6, log)
@@ -239,7 +296,16 @@ func (r *TestRunReconciler) reconcile(ctx context.Context, req ctrl.Request, log
 					return ctrl.Result{RequeueAfter: time.Second * 15}, nil
 				}
 			}
-		} else if !FinishJobs(ctx, log, k6, r) {
+		} else if v1alpha1.IsFalse(k6, v1alpha1.ReportGenerated) {
+			// Runners linger until the results are collected.
+			return CollectReport(ctx, log, k6, r)
+		} else if v1alpha1.IsTrue(k6, v1alpha1.ReportGenerated) {
+			// Lingering runners are stopped once the results are collected:
+			// the test run is stopped only after all of them are deleted.
+			if allDeleted, err := KillJobs(ctx, log, k6, r); err != nil || !allDeleted {
+				return ctrl.Result{RequeueAfter: time.Second * 2}, err
+			}
+		} else if v1alpha1.IsUnknown(k6, v1alpha1.ReportGenerated) && !FinishJobs(ctx, log, k6, r) {
 			// wait for the test to finish
 
 			// TODO: confirm if this check is needed given the check in the beginning of reconcile
@@ -263,6 +329,9 @@ func (r *TestRunReconciler) reconcile(ctx context.Context, req ctrl.Request, log
 		// now mark it as stopped
 
 		if v1alpha1.IsTrue(k6, v1alpha1.TestRunRunning) {
+			CollectRunnerErrors(ctx, log, k6, r)
+			RecommendResources(ctx, log, k6, r)
+
 			v1alpha1.UpdateCondition(k6, v1alpha1.TestRunRunning, metav1.ConditionFalse)
 
 			log.Info("Changing stage of TestRun status to stopped")
@@ -285,13 +354,16 @@ func (r *TestRunReconciler) reconcile(ctx context.Context, req ctrl.Request, log
 				if allDeleted, err := KillJobs(ctx, log, k6, r); err != nil {
 					return ctrl.Result{RequeueAfter: time.Second}, err
 				} else {
+					// runners may still be stopping and flushing their outputs
+					if !allDeleted {
+						return ctrl.Result{RequeueAfter: time.Second * 2}, nil
+					}
+
 					// if we just have deleted all jobs, update status and go for reconcile
-					if allDeleted {
-						v1alpha1.UpdateCondition(k6, v1alpha1.CloudTestRunAborted, metav1.ConditionTrue)
-						_, err := r.UpdateStatus(ctx, k6, log)
-						if err != nil {
-							return ctrl.Result{}, err
-						}
+					v1alpha1.UpdateCondition(k6, v1alpha1.CloudTestRunAborted, metav1.ConditionTrue)
+					_, err := r.UpdateStatus(ctx, k6, log)
+					if err != nil {
+						return ctrl.Result{}, err
 					}
 				}
 			}
@@ -308,7 +380,7 @@ func (r *TestRunReconciler) reconcile(ctx context.Context, req ctrl.Request, log
 				return ctrl.Result{RequeueAfter: time.Second * 2}, nil
 			}
 
-			if err = cloud.FinishTestRun(r.k6CloudClient, k6.GetStatus().TestRunID); err != nil {
+			if err = r.k6CloudClient.FinishTestRun(k6.GetStatus().TestRunID); err != nil {
 				log.Error(err, "Failed to finalize the test run with cloud output")
 				return ctrl.Result{}, nil
 			} else {
@@ -390,6 +462,7 @@ func (r *TestRunReconciler) UpdateStatus(ctx context.Context, k6 *v1alpha1.TestR
 	}
 
 	cleanObj := k6.DeepCopyObject().(client.Object)
+	previousStage := k6.GetStatus().Stage
 
 	// Update only if it's truly a newer version of the resource
 	// in comparison to the recently fetched resource.
@@ -412,6 +485,10 @@ func (r *TestRunReconciler) UpdateStatus(ctx context.Context, k6 *v1alpha1.TestR
 		return false, err
 	}
 
+	if k6.GetStatus().Stage != previousStage {
+		r.stageChanged(ctx, log, k6, previousStage)
+	}
+
 	return true, nil
 }
 
@@ -424,7 +501,7 @@ func (r *TestRunReconciler) ShouldAbort(ctx context.Context, k6 *v1alpha1.TestRu
 		return false
 	}
 
-	status, err := cloud.GetTestRunState(r.k6CloudClient, k6.TestRunID(), log)
+	status, err := r.k6CloudClient.GetTestRunState(k6.TestRunID(), log)
 	if err != nil {
 		log.Error(err, "Failed to get test run state.")
 		return false
@@ -450,7 +527,7 @@ func (r *TestRunReconciler) createClient(ctx context.Context, k6 *v1alpha1.TestR
 
 		host := getEnvVar(k6.GetSpec().Runner.Env, "K6_CLOUD_HOST")
 
-		r.k6CloudClient = cloud.NewClient(log, token, host)
+		r.setCloudClient(log, token, host)
 	}
 
 	return true, nil
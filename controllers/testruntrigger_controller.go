@@ -0,0 +1,307 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TestRunTriggerReconciler reconciles a TestRunTrigger object
+type TestRunTriggerReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=k6.io,resources=testruntriggers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k6.io,resources=testruntriggers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k6.io,resources=testruntriggers/finalizers,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+
+// Reconcile creates a TestRun from the template of the trigger once its script
+// or workload has changed and stayed unchanged for the debounce period.
+// For a workload, the rollout must complete as well.
+func (r *TestRunTriggerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("namespace", req.Namespace, "name", req.Name, "reconcileID", controller.ReconcileIDFromContext(ctx))
+
+	trigger := &v1alpha1.TestRunTrigger{}
+	if err := r.Get(ctx, req.NamespacedName, trigger); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			log.Info("Request deleted. Nothing to reconcile.")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Could not fetch request")
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	if trigger.Spec.Suspend {
+		return ctrl.Result{}, nil
+	}
+
+	revision, image, settled, res, err := r.currentRevision(ctx, trigger, log)
+	if len(revision) == 0 {
+		return res, err
+	}
+
+	var (
+		clean = trigger.DeepCopy()
+		now   = metav1.Now()
+	)
+
+	// The revision at creation of the trigger is considered tested:
+	// only changes trigger test runs.
+	if len(trigger.Status.TriggeredRevision) == 0 {
+		trigger.Status.TriggeredRevision = revision
+		trigger.Status.ObservedRevision = revision
+		trigger.Status.ObservedTime = &now
+		return ctrl.Result{}, r.updateStatus(ctx, trigger, clean, log)
+	}
+
+	if revision != trigger.Status.ObservedRevision || trigger.Status.ObservedTime == nil {
+		log.Info(fmt.Sprintf("Observed new revision %s.", revision))
+
+		trigger.Status.ObservedRevision = revision
+		trigger.Status.ObservedTime = &now
+		if err := r.updateStatus(ctx, trigger, clean, log); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: trigger.DebounceDuration()}, nil
+	}
+
+	if revision == trigger.Status.TriggeredRevision {
+		return ctrl.Result{}, nil
+	}
+
+	if !settled {
+		// changes of the workload requeue the trigger
+		log.Info(fmt.Sprintf("Waiting for the rollout of revision %s to complete.", revision))
+		return ctrl.Result{}, nil
+	}
+
+	if wait := trigger.DebounceDuration() - time.Since(trigger.Status.ObservedTime.Time); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	active, err := r.activeTestRuns(ctx, trigger)
+	if err != nil {
+		log.Error(err, "Failed to list test runs of the trigger")
+		return ctrl.Result{}, err
+	}
+
+	if len(active) > 0 {
+		switch trigger.Policy() {
+		case v1alpha1.ForbidConcurrent:
+			// finished test runs of the trigger requeue it as well
+			log.Info(fmt.Sprintf("Waiting for %d test runs to finish before testing revision %s.", len(active), revision))
+			return ctrl.Result{RequeueAfter: time.Second * 30}, nil
+
+		case v1alpha1.ReplaceConcurrent:
+			for i := range active {
+				if err := r.Delete(ctx, &active[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !k8sErrors.IsNotFound(err) {
+					log.Error(err, fmt.Sprintf("Failed to delete test run %s", active[i].Name))
+					return ctrl.Result{}, err
+				}
+				log.Info(fmt.Sprintf("Deleted test run %s to replace it.", active[i].Name))
+			}
+		}
+	}
+
+	k6 := testrun.NewTriggeredTestRun(trigger, revision, image, *trigger.Status.ObservedTime)
+	if err := ctrl.SetControllerReference(trigger, k6, r.Scheme); err != nil {
+		log.Error(err, "Failed to set controller reference for the test run")
+		return ctrl.Result{}, err
+	}
+
+	// the name is unique per change so an existing test run was created by a previous reconcile
+	if err := r.Create(ctx, k6); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		log.Error(err, "Failed to create test run")
+		return ctrl.Result{}, err
+	}
+
+	log.Info(fmt.Sprintf("Created test run %s for revision %s.", k6.Name, revision))
+
+	trigger.Status.TriggeredRevision = revision
+	trigger.Status.LastTestRun = k6.Name
+	trigger.Status.LastTriggerTime = &now
+
+	return ctrl.Result{}, r.updateStatus(ctx, trigger, clean, log)
+}
+
+// currentRevision returns the revision of the script or the workload of the trigger.
+// For a workload, it also returns the image of the rollout and whether the rollout
+// has completed. An empty revision means that reconcile should stop with res and err.
+func (r *TestRunTriggerReconciler) currentRevision(ctx context.Context, trigger *v1alpha1.TestRunTrigger, log logr.Logger) (
+	revision, image string, settled bool, res ctrl.Result, err error,
+) {
+	if source, ok := trigger.Workload(); ok {
+		log = log.WithValues("workload", source.String())
+
+		workload, err := testrun.NewWorkload(trigger.Spec.Rollout.Kind)
+		if err != nil {
+			log.Error(err, "Invalid rollout of the trigger")
+			return "", "", false, ctrl.Result{}, nil
+		}
+
+		if err = r.Get(ctx, source, workload); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				log.Info("Waiting for the workload to be created.")
+				return "", "", false, ctrl.Result{}, nil
+			}
+			log.Error(err, "Failed to get the workload")
+			return "", "", false, ctrl.Result{}, err
+		}
+
+		if revision, err = testrun.RolloutRevision(workload, trigger.Spec.Rollout); err != nil {
+			log.Error(err, "Invalid rollout of the trigger")
+			return "", "", false, ctrl.Result{}, nil
+		}
+		if image, err = testrun.RolloutImage(workload, trigger.Spec.Rollout); err != nil {
+			log.Error(err, "Invalid rollout of the trigger")
+			return "", "", false, ctrl.Result{}, nil
+		}
+
+		return revision, image, testrun.RolloutComplete(workload), ctrl.Result{}, nil
+	}
+
+	source, ok := trigger.Script()
+	if !ok {
+		log.Info("Only a script in ConfigMap can trigger test runs.")
+		return "", "", false, ctrl.Result{}, nil
+	}
+	log = log.WithValues("script", source.String())
+
+	if source.Namespace != trigger.Namespace {
+		granted, err := isScriptGranted(ctx, r.Client, trigger.Namespace, source)
+		if err != nil {
+			log.Error(err, "Failed to list script grants")
+			return "", "", false, ctrl.Result{}, err
+		}
+		if !granted {
+			log.Info(fmt.Sprintf("No ScriptGrant in namespace %s allows namespace %s to use ConfigMap %s.",
+				source.Namespace, trigger.Namespace, source.Name))
+			// grants are not watched
+			return "", "", false, ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, source, cm); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			log.Info("Waiting for the script ConfigMap to be created.")
+			return "", "", false, ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get the script ConfigMap")
+		return "", "", false, ctrl.Result{}, err
+	}
+
+	return testrun.ScriptRevision(cm), "", true, ctrl.Result{}, nil
+}
+
+// activeTestRuns lists test runs of the trigger that are not finished yet.
+// Test runs of the analysis endpoint are left to their callers.
+func (r *TestRunTriggerReconciler) activeTestRuns(ctx context.Context, trigger *v1alpha1.TestRunTrigger) ([]v1alpha1.TestRun, error) {
+	list := &v1alpha1.TestRunList{}
+	if err := r.List(ctx, list, client.InNamespace(trigger.Namespace), client.MatchingLabels{
+		v1alpha1.TriggerLabel: trigger.Name,
+	}); err != nil {
+		return nil, err
+	}
+
+	var active []v1alpha1.TestRun
+	for _, k6 := range list.Items {
+		if _, ok := k6.Labels[v1alpha1.AnalysisLabel]; ok {
+			continue
+		}
+		if k6.Status.Stage != "finished" && k6.Status.Stage != "error" && k6.DeletionTimestamp.IsZero() {
+			active = append(active, k6)
+		}
+	}
+	return active, nil
+}
+
+func (r *TestRunTriggerReconciler) updateStatus(ctx context.Context, trigger, clean *v1alpha1.TestRunTrigger, log logr.Logger) error {
+	if err := r.Status().Patch(ctx, trigger, client.MergeFrom(clean)); err != nil {
+		log.Error(err, "Could not update status of custom resource")
+		return err
+	}
+	return nil
+}
+
+// triggersOf maps a ConfigMap or a workload to the triggers watching it.
+func (r *TestRunTriggerReconciler) triggersOf(ctx context.Context, object client.Object) []reconcile.Request {
+	triggers := &v1alpha1.TestRunTriggerList{}
+	if err := r.List(ctx, triggers); err != nil {
+		r.Log.Error(err, "Failed to list test run triggers")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range triggers.Items {
+		var (
+			source types.NamespacedName
+			ok     bool
+		)
+		switch object.(type) {
+		case *corev1.ConfigMap:
+			if _, isRollout := triggers.Items[i].Workload(); !isRollout {
+				source, ok = triggers.Items[i].Script()
+			}
+		case *appsv1.Deployment:
+			source, ok = triggers.Items[i].Workload()
+			ok = ok && triggers.Items[i].Spec.Rollout.Kind == "Deployment"
+		case *appsv1.StatefulSet:
+			source, ok = triggers.Items[i].Workload()
+			ok = ok && triggers.Items[i].Spec.Rollout.Kind == "StatefulSet"
+		}
+
+		if ok && source.Namespace == object.GetNamespace() && source.Name == object.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: triggers.Items[i].Namespace,
+				Name:      triggers.Items[i].Name,
+			}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TestRunTriggerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.TestRunTrigger{}).
+		Owns(&v1alpha1.TestRun{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.triggersOf)).
+		Watches(&appsv1.Deployment{}, handler.EnqueueRequestsFromMapFunc(r.triggersOf)).
+		Watches(&appsv1.StatefulSet{}, handler.EnqueueRequestsFromMapFunc(r.triggersOf)).
+		Complete(r)
+}
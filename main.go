@@ -17,10 +17,21 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/grafana/k6-operator/controllers"
+	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/grafana/k6-operator/pkg/health"
+	"github.com/grafana/k6-operator/pkg/local"
+	"github.com/grafana/k6-operator/pkg/testrun"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -28,7 +39,6 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
-	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -50,23 +60,47 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "local" {
+		os.Exit(runLocal(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var healthAddr string
 	var enableLeaderElection bool
+	var plzWebhookAddr string
+	var analysisAddr string
+	var alertmanagerAddr string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&healthAddr, "health-addr", ":8081", "The address the health endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&plzWebhookAddr, "plz-webhook-addr", "",
+		"The address the webhook for new PLZ test runs binds to. "+
+			"Requires K6_PLZ_WEBHOOK_SECRET to verify requests. "+
+			"If set, k6 Cloud is polled for new PLZ test runs only as a fallback.")
+	flag.StringVar(&analysisAddr, "analysis-addr", "",
+		"The address the analysis endpoint for Argo Rollouts and Flagger binds to. "+
+			"Requires K6_ANALYSIS_TOKEN to authenticate requests.")
+	flag.StringVar(&alertmanagerAddr, "alertmanager-addr", "",
+		"The address the Alertmanager webhook receiver for spec.alertActions binds to. "+
+			"Requires K6_ALERTMANAGER_TOKEN to authenticate requests.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
 
+	// detailed state of health checks is served next to metrics
+	healthChecks := &health.Registry{}
+
 	mgrOpts := ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
+			ExtraHandlers: map[string]http.Handler{
+				health.DebugPath: healthChecks,
+			},
 		},
+		HealthProbeBindAddress: healthAddr,
 		WebhookServer: webhook.NewServer(webhook.Options{
 			Port: 9443,
 		}),
@@ -89,25 +123,83 @@ func main() {
 		os.Exit(1)
 	}
 
-	_ = mgr.AddHealthzCheck("health", healthz.Ping)
-	_ = mgr.AddReadyzCheck("ready", healthz.Ping)
-
-	if err = (&controllers.TestRunReconciler{
+	testRunReconciler := &controllers.TestRunReconciler{
 		Client: mgr.GetClient(),
 		Log:    ctrl.Log.WithName("controllers").WithName("TestRun"),
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	}
+	if err = testRunReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TestRun")
 		os.Exit(1)
 	}
-	if err = (&controllers.PrivateLoadZoneReconciler{
+	if len(alertmanagerAddr) > 0 {
+		token, ok := os.LookupEnv("K6_ALERTMANAGER_TOKEN")
+		if !ok || len(token) == 0 {
+			setupLog.Error(errors.New("K6_ALERTMANAGER_TOKEN is not set"), "unable to set up Alertmanager webhook")
+			os.Exit(1)
+		}
+
+		if err = mgr.Add(testRunReconciler.AlertmanagerServer(alertmanagerAddr, token)); err != nil {
+			setupLog.Error(err, "unable to set up Alertmanager webhook")
+			os.Exit(1)
+		}
+	}
+	plzReconciler := &controllers.PrivateLoadZoneReconciler{
 		Client: mgr.GetClient(),
 		Log:    ctrl.Log.WithName("controllers").WithName("PrivateLoadZone"),
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	}
+	if err = plzReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PrivateLoadZone")
 		os.Exit(1)
 	}
+	triggerReconciler := &controllers.TestRunTriggerReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("TestRunTrigger"),
+		Scheme: mgr.GetScheme(),
+	}
+	if err = triggerReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TestRunTrigger")
+		os.Exit(1)
+	}
+	if len(analysisAddr) > 0 {
+		token, ok := os.LookupEnv("K6_ANALYSIS_TOKEN")
+		if !ok || len(token) == 0 {
+			setupLog.Error(errors.New("K6_ANALYSIS_TOKEN is not set"), "unable to set up analysis endpoint")
+			os.Exit(1)
+		}
+
+		if err = mgr.Add(triggerReconciler.AnalysisServer(analysisAddr, token)); err != nil {
+			setupLog.Error(err, "unable to set up analysis endpoint")
+			os.Exit(1)
+		}
+	}
+	if len(plzWebhookAddr) > 0 {
+		secret, ok := os.LookupEnv("K6_PLZ_WEBHOOK_SECRET")
+		if !ok || len(secret) == 0 {
+			setupLog.Error(errors.New("K6_PLZ_WEBHOOK_SECRET is not set"), "unable to set up PLZ webhook")
+			os.Exit(1)
+		}
+
+		plzReconciler.PollingInterval = cloud.FallbackPollingInterval
+		if err = mgr.Add(plzReconciler.WebhookServer(plzWebhookAddr, []byte(secret))); err != nil {
+			setupLog.Error(err, "unable to set up PLZ webhook")
+			os.Exit(1)
+		}
+	}
+
+	checks := []health.Check{{
+		Name:    "cache",
+		Checker: health.CacheSynced(mgr.GetCache()),
+	}}
+	checks = append(checks, testRunReconciler.HealthChecks()...)
+	checks = append(checks, plzReconciler.HealthChecks()...)
+	for _, check := range checks {
+		if err = healthChecks.Add(mgr, check); err != nil {
+			setupLog.Error(err, "unable to set up health check", "check", check.Name)
+			os.Exit(1)
+		}
+	}
 
 	// +kubebuilder:scaffold:builder
 
@@ -123,3 +215,40 @@ func getWatchNamespace() (string, bool) {
 
 	return os.LookupEnv(watchNamespaceEnvVar)
 }
+
+// runLocal executes a TestRun with local k6 processes instead of a cluster.
+// The exit code is 99 if thresholds failed, as with k6.
+func runLocal(args []string) int {
+	opts := local.Options{Out: os.Stdout}
+
+	fs := flag.NewFlagSet("local", flag.ExitOnError)
+	fs.StringVar(&opts.File, "f", "", "The manifest of the TestRun.")
+	fs.StringVar(&opts.Script, "script", "",
+		"The path of the script. Defaults to the file of spec.script next to the manifest.")
+	fs.StringVar(&opts.K6, "k6", "k6", "The k6 binary.")
+	fs.IntVar(&opts.Port, "port", testrun.DefaultPort,
+		"The port of the REST API of the first runner; the other runners listen on the next ones.")
+	fs.DurationVar(&opts.Interval, "interval", 5*time.Second, "The interval between status reports.")
+	_ = fs.Parse(args)
+
+	if len(opts.File) == 0 {
+		fmt.Fprintf(os.Stderr, "usage: %s local -f testrun.yaml [flags]\n", os.Args[0])
+		fs.PrintDefaults()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	summary, err := local.Run(ctx, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	local.PrintSummary(os.Stdout, summary)
+	if !summary.ThresholdsPassed {
+		return 99
+	}
+	return 0
+}
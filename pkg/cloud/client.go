@@ -0,0 +1,66 @@
+package cloud
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"go.k6.io/k6/cloudapi"
+)
+
+// Client is the k6 Cloud API as used by the TestRun controller. It can be
+// replaced by projects embedding the controller, e.g. to go through a proxy
+// or to record test runs elsewhere.
+type Client interface {
+	// CreateTestRun creates a cloud output test run.
+	CreateTestRun(opts InspectOutput, instances int32) (*cloudapi.CreateTestRunResponse, error)
+	// FinishTestRun marks the cloud output test run as finished.
+	FinishTestRun(refID string) error
+	// GetTestRunState returns the run status of the test run.
+	GetTestRunState(refID string, log logr.Logger) (TestRunStatus, error)
+	// SendTestRunEvents sends the events of the test run. Errors are only logged.
+	SendTestRunEvents(refID string, log logr.Logger, events *Events)
+	// Ping verifies that the API is reachable.
+	Ping(ctx context.Context) error
+}
+
+// NewClientFunc creates a Client from the token and the host of k6 Cloud.
+type NewClientFunc func(log logr.Logger, token, host string) Client
+
+// APIClient is the Client of the k6 Cloud API.
+type APIClient struct {
+	client *cloudapi.Client
+	host   string
+}
+
+var _ Client = &APIClient{}
+
+// NewAPIClient is the default NewClientFunc.
+func NewAPIClient(log logr.Logger, token, host string) Client {
+	if len(host) == 0 {
+		host = cloudapi.NewConfig().Host.String
+	}
+	return &APIClient{
+		client: NewClient(log, token, host),
+		host:   host,
+	}
+}
+
+func (c *APIClient) CreateTestRun(opts InspectOutput, instances int32) (*cloudapi.CreateTestRunResponse, error) {
+	return CreateTestRun(c.client, c.host, opts, instances)
+}
+
+func (c *APIClient) FinishTestRun(refID string) error {
+	return FinishTestRun(c.client, refID)
+}
+
+func (c *APIClient) GetTestRunState(refID string, log logr.Logger) (TestRunStatus, error) {
+	return GetTestRunState(c.client, refID, log)
+}
+
+func (c *APIClient) SendTestRunEvents(refID string, log logr.Logger, events *Events) {
+	SendTestRunEvents(c.client, refID, log, events)
+}
+
+func (c *APIClient) Ping(ctx context.Context) error {
+	return Ping(ctx, c.client)
+}
@@ -12,9 +12,6 @@ import (
 	null "gopkg.in/guregu/null.v3"
 )
 
-// TODO: refactor this!
-var client *cloudapi.Client
-
 type TestRun struct {
 	Name              string              `json:"name"`
 	ProjectID         int64               `json:"project_id,omitempty"`
@@ -42,11 +39,8 @@ func NewClient(log logr.Logger, token, host string) *cloudapi.Client {
 	return cloudapi.NewClient(logger, token, host, consts.Version, time.Duration(time.Minute))
 }
 
-func CreateTestRun(opts InspectOutput, instances int32, host, token string, log logr.Logger) (*cloudapi.CreateTestRunResponse, error) {
-	if client == nil {
-		client = NewClient(log, token, host)
-	}
-
+// CreateTestRun creates a cloud output test run with the thresholds of the test.
+func CreateTestRun(client *cloudapi.Client, host string, opts InspectOutput, instances int32) (*cloudapi.CreateTestRunResponse, error) {
 	cloudConfig := cloudapi.NewConfig()
 
 	if opts.ProjectID() > 0 {
@@ -64,10 +58,6 @@ func CreateTestRun(opts InspectOutput, instances int32, host, token string, log
 		host = cloudConfig.Host.String
 	}
 
-	if client == nil {
-		client = NewClient(log, token, host)
-	}
-
 	tr := TestRun{
 		Name:              opts.TestName(),
 		ProjectID:         cloudConfig.ProjectID.Int64,
@@ -103,13 +93,7 @@ func createTestRun(client *cloudapi.Client, host string, testRun *TestRun) (*clo
 }
 
 func FinishTestRun(c *cloudapi.Client, refID string) error {
-	if c != nil {
-		return c.TestFinished(refID, cloudapi.ThresholdResult(
-			map[string]map[string]bool{},
-		), false, cloudapi.RunStatusFinished)
-	}
-
-	return client.TestFinished(refID, cloudapi.ThresholdResult(
+	return c.TestFinished(refID, cloudapi.ThresholdResult(
 		map[string]map[string]bool{},
 	), false, cloudapi.RunStatusFinished)
 }
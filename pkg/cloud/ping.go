@@ -0,0 +1,30 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.k6.io/k6/cloudapi"
+)
+
+// Ping verifies that k6 Cloud API of the client is reachable. The request
+// is not authenticated so any response below 500 is fine.
+func Ping(ctx context.Context, client *cloudapi.Client) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.BaseURL(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("k6 Cloud API responded with %s", resp.Status)
+	}
+
+	return nil
+}
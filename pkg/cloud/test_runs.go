@@ -1,9 +1,12 @@
 package cloud
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -13,18 +16,52 @@ import (
 	"go.k6.io/k6/lib/consts"
 )
 
+const (
+	// PollingInterval is the interval of polling k6 Cloud for new PLZ test runs.
+	PollingInterval = 10 * time.Second
+	// FallbackPollingInterval is the interval of polling k6 Cloud when new PLZ
+	// test runs are pushed to the webhook: polling only catches missed events then.
+	FallbackPollingInterval = 5 * time.Minute
+)
+
 type TestRunPoller struct {
 	*conn.Poller
 
 	token     string
 	host      string
+	plzName   string
+	interval  time.Duration
 	logger    logr.Logger
 	testRunCh chan string
 
+	// guards testRunCh from sending after it is closed
+	chMu     sync.RWMutex
+	chClosed bool
+
+	mu     sync.RWMutex
+	health PollerHealth
+
 	Client *cloudapi.Client
 }
 
-func NewTestRunPoller(host, token, plzName string, logger logr.Logger) *TestRunPoller {
+// PollerHealth describes the state of polling k6 Cloud.
+type PollerHealth struct {
+	// Registered tells whether the PLZ is registered with k6 Cloud,
+	// i.e. whether the poller is expected to be polling.
+	Registered bool          `json:"registered"`
+	Polling    bool          `json:"polling"`
+	Interval   time.Duration `json:"interval"`
+	// Started is the last time polling was started.
+	Started time.Time `json:"started,omitempty"`
+	// LastAttempt is the last time the poller requested k6 Cloud,
+	// regardless of the outcome.
+	LastAttempt time.Time `json:"lastAttempt,omitempty"`
+	// LastSuccess is the last time the poller retrieved test runs.
+	LastSuccess time.Time `json:"lastSuccess,omitempty"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+func NewTestRunPoller(host, token, plzName string, interval time.Duration, logger logr.Logger) *TestRunPoller {
 	logrusLogger := &logrus.Logger{
 		Out:       os.Stdout,
 		Formatter: new(logrus.TextFormatter),
@@ -34,16 +71,15 @@ func NewTestRunPoller(host, token, plzName string, logger logr.Logger) *TestRunP
 
 	testRunsCh := make(chan string)
 
-	poller := conn.NewPoller(10 * time.Second)
-	poller.OnDone = func() {
-		close(testRunsCh)
-	}
+	poller := conn.NewPoller(interval)
 
-	testRunPoller := TestRunPoller{
+	testRunPoller := &TestRunPoller{
 		Poller: poller,
 
 		token:     token,
 		host:      host,
+		plzName:   plzName,
+		interval:  interval,
 		logger:    logger,
 		testRunCh: testRunsCh,
 
@@ -52,6 +88,7 @@ func NewTestRunPoller(host, token, plzName string, logger logr.Logger) *TestRunP
 
 	testRunPoller.Poller.OnInterval = func() {
 		list, err := testRunPoller.getTestRuns(plzName)
+		testRunPoller.recordPoll(err)
 		if err != nil {
 			logger.Error(err, "Failed to get test runs from k6 Cloud.")
 		} else {
@@ -63,7 +100,104 @@ func NewTestRunPoller(host, token, plzName string, logger logr.Logger) *TestRunP
 		}
 	}
 
-	return &testRunPoller
+	testRunPoller.Poller.OnDone = func() {
+		testRunPoller.chMu.Lock()
+		defer testRunPoller.chMu.Unlock()
+
+		testRunPoller.chClosed = true
+		close(testRunsCh)
+	}
+
+	return testRunPoller
+}
+
+// Push passes a test run ID received outside of polling, e.g. from the webhook,
+// to the consumer of GetTestRuns, together with polled test runs.
+func (poller *TestRunPoller) Push(ctx context.Context, testRunId string) error {
+	poller.chMu.RLock()
+	defer poller.chMu.RUnlock()
+
+	if poller.chClosed {
+		return errors.New("poller is stopped")
+	}
+
+	select {
+	case poller.testRunCh <- testRunId:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PLZName returns the name of the PLZ the poller is polling for.
+func (poller *TestRunPoller) PLZName() string {
+	return poller.plzName
+}
+
+func (poller *TestRunPoller) recordPoll(err error) {
+	poller.mu.Lock()
+	defer poller.mu.Unlock()
+
+	now := time.Now()
+	poller.health.LastAttempt = now
+	if err != nil {
+		poller.health.LastError = err.Error()
+	} else {
+		poller.health.LastSuccess = now
+		poller.health.LastError = ""
+	}
+}
+
+// Start starts polling k6 Cloud.
+func (poller *TestRunPoller) Start() {
+	poller.mu.Lock()
+	poller.health.Started = time.Now()
+	poller.mu.Unlock()
+
+	poller.Poller.Start()
+}
+
+// SetRegistered records whether the PLZ of the poller is registered with k6 Cloud.
+func (poller *TestRunPoller) SetRegistered(registered bool) {
+	poller.mu.Lock()
+	defer poller.mu.Unlock()
+
+	poller.health.Registered = registered
+}
+
+// Health returns the current state of polling.
+func (poller *TestRunPoller) Health() PollerHealth {
+	poller.mu.RLock()
+	defer poller.mu.RUnlock()
+
+	health := poller.health
+	health.Polling = poller.IsPolling()
+	health.Interval = poller.interval
+	return health
+}
+
+// Stalled returns an error if the PLZ is registered but the poller isn't
+// polling, or if there was no attempt to poll within the intervals, be it
+// since the last attempt or since polling was started.
+func (h PollerHealth) Stalled(intervals int, now time.Time) error {
+	if !h.Registered {
+		return nil
+	}
+	if !h.Polling {
+		return errors.New("PLZ is registered but the poller is not polling")
+	}
+
+	timeout := time.Duration(intervals) * h.Interval
+	if h.LastAttempt.IsZero() {
+		if since := now.Sub(h.Started); since > timeout {
+			return fmt.Errorf("PLZ poller is stuck: no poll since it started %s ago", since.Round(time.Second))
+		}
+		return nil
+	}
+	if since := now.Sub(h.LastAttempt); since > timeout {
+		return fmt.Errorf("PLZ poller is stuck: last poll was %s ago", since.Round(time.Second))
+	}
+	return nil
 }
 
 func (poller *TestRunPoller) GetTestRuns() chan string {
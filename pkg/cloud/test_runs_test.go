@@ -0,0 +1,90 @@
+package cloud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollerHealthStalled(t *testing.T) {
+	now := time.Now()
+	interval := 10 * time.Second
+
+	tests := []struct {
+		name    string
+		health  PollerHealth
+		stalled bool
+	}{
+		{
+			name:   "not registered",
+			health: PollerHealth{Interval: interval},
+		},
+		{
+			name:    "registered but not polling",
+			health:  PollerHealth{Registered: true, Interval: interval},
+			stalled: true,
+		},
+		{
+			name:   "waiting for the first poll",
+			health: PollerHealth{Registered: true, Polling: true, Interval: interval, Started: now.Add(-time.Minute)},
+		},
+		{
+			name:    "no first poll",
+			health:  PollerHealth{Registered: true, Polling: true, Interval: interval, Started: now.Add(-2 * time.Minute)},
+			stalled: true,
+		},
+		{
+			name: "recent poll",
+			health: PollerHealth{Registered: true, Polling: true, Interval: interval,
+				Started: now.Add(-time.Hour), LastAttempt: now.Add(-interval)},
+		},
+		{
+			name: "stuck",
+			health: PollerHealth{Registered: true, Polling: true, Interval: interval,
+				Started: now.Add(-time.Hour), LastAttempt: now.Add(-2 * time.Minute)},
+			stalled: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.health.Stalled(6, now)
+			assert.Equal(t, test.stalled, err != nil, err)
+		})
+	}
+}
+
+func TestTestRunPollerPush(t *testing.T) {
+	poller := NewTestRunPoller("http://127.0.0.1:1", "token", "my-plz", time.Hour, logr.Discard())
+	poller.Start()
+
+	received := make(chan string, 1)
+	go func() {
+		for testRunId := range poller.GetTestRuns() {
+			received <- testRunId
+		}
+		close(received)
+	}()
+
+	assert.NoError(t, poller.Push(context.Background(), "123"))
+	assert.Equal(t, "123", <-received)
+
+	poller.Stop()
+	_, open := <-received
+	assert.False(t, open, "test runs channel should be closed once the poller stops")
+
+	assert.EqualError(t, poller.Push(context.Background(), "456"), "poller is stopped")
+}
+
+func TestTestRunPollerPushTimeout(t *testing.T) {
+	poller := NewTestRunPoller("http://127.0.0.1:1", "token", "my-plz", time.Hour, logr.Discard())
+
+	// nothing consumes the test runs
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, poller.Push(ctx, "123"), context.DeadlineExceeded)
+}
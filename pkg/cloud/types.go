@@ -3,11 +3,11 @@ package cloud
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"go.k6.io/k6/cloudapi"
 	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
-	corev1 "k8s.io/api/core/v1"
 )
 
 // InspectOutput is the parsed output from `k6 inspect --execution-requirements`.
@@ -25,6 +25,32 @@ type InspectOutput struct {
 	TotalDuration types.NullDuration             `json:"totalDuration"`
 	MaxVUs        uint64                         `json:"maxVUs"`
 	Thresholds    map[string]*metrics.Thresholds `json:"thresholds,omitempty"`
+	Scenarios     map[string]struct {
+		Executor string `json:"executor"`
+	} `json:"scenarios,omitempty"`
+}
+
+// durationBasedExecutors run for a fixed time rather than a number of iterations.
+var durationBasedExecutors = map[string]bool{
+	"constant-vus":          true,
+	"ramping-vus":           true,
+	"constant-arrival-rate": true,
+	"ramping-arrival-rate":  true,
+}
+
+// DurationBased tells if all scenarios use executors that run for a fixed
+// time, so that a part of the load can be restarted without redoing
+// iterations of the other parts.
+func (io *InspectOutput) DurationBased() bool {
+	if len(io.Scenarios) == 0 {
+		return false
+	}
+	for _, scenario := range io.Scenarios {
+		if !durationBasedExecutors[scenario.Executor] {
+			return false
+		}
+	}
+	return true
 }
 
 // ProjectID returns the project ID from the inspect output.
@@ -67,32 +93,38 @@ type TestRunData struct {
 }
 
 type LZConfig struct {
-	RunnerImage   string            `json:"load_runner_image,omitempty"`
-	InstanceCount int               `json:"instance_count,omitempty"`
-	ArchiveURL    string            `json:"k6_archive_temp_public_url,omitempty"`
-	Environment   map[string]string `json:"environment,omitempty"`
+	RunnerImage   string      `json:"load_runner_image,omitempty"`
+	InstanceCount int         `json:"instance_count,omitempty"`
+	ArchiveURL    string      `json:"k6_archive_temp_public_url,omitempty"`
+	Environment   Environment `json:"environment,omitempty"`
 }
 
 func (trd *TestRunData) TestRunID() string {
 	return fmt.Sprintf("%d", trd.TestRunId)
 }
 
-func (lz *LZConfig) EnvVars() []corev1.EnvVar {
-	ev := make([]corev1.EnvVar, len(lz.Environment))
-	i := 0
-	for k, v := range lz.Environment {
-		ev[i] = corev1.EnvVar{
-			Name:  k,
-			Value: v,
-		}
-		i++
+// Environment is the environment of the runners configured in k6 Cloud.
+// It may contain secrets, so its values are masked when it is formatted,
+// e.g. in logs.
+type Environment map[string]string
+
+// Names returns the sorted names of the variables.
+func (e Environment) Names() []string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
 	}
-	// to have deterministic order in the array
-	sort.Slice(ev, func(i, j int) bool {
-		return ev[i].Name < ev[j].Name
-	})
+	sort.Strings(names)
+	return names
+}
 
-	return ev
+// Format implements fmt.Formatter and prints the names of the variables only.
+func (e Environment) Format(f fmt.State, _ rune) {
+	masked := make([]string, len(e))
+	for i, name := range e.Names() {
+		masked[i] = name + "=***"
+	}
+	fmt.Fprintf(f, "map[%s]", strings.Join(masked, " "))
 }
 
 type TestRunStatus cloudapi.RunStatus
@@ -103,6 +135,11 @@ func (trs TestRunStatus) Aborted() bool {
 	return cloudapi.RunStatus(trs) >= cloudapi.RunStatusTimedOut
 }
 
+// Ended tells whether the test run has finished, timed out or was aborted.
+func (trs TestRunStatus) Ended() bool {
+	return cloudapi.RunStatus(trs) >= cloudapi.RunStatusFinished
+}
+
 // func (trs TestRunStatus) String() string {
 // 	TODO: for a pretty output about test run status in the logs?
 // }
@@ -189,6 +226,17 @@ func (e *Events) WithDetail(s string) *Events {
 	return e
 }
 
+// WithPublicDetail sets the detail shown to users only for the 1st event,
+// if it's not abort.
+func (e *Events) WithPublicDetail(s string) *Events {
+	if len(*e) == 0 || (*e)[0].EventType == abortEvent {
+		return e
+	}
+
+	(*e)[0].PublicDetail = s
+	return e
+}
+
 // WithAbort adds abortEvent to errorEvent if it already exists.
 func (e *Events) WithAbort() *Events {
 	if len(*e) == 0 {
@@ -2,7 +2,11 @@ package cloud
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+
+	"go.k6.io/k6/cloudapi"
 )
 
 func TestInspectOutput_TestNameAndProjectID(t *testing.T) {
@@ -73,3 +77,68 @@ func TestInspectOutput_SetTestName(t *testing.T) {
 		t.Errorf("InspectOutput.TestName() = %v, want test-lore-ipsum", got)
 	}
 }
+
+func TestInspectOutput_DurationBased(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"no scenarios", `{"maxVUs":10}`, false},
+		{"duration-based", `{"scenarios":{"soak":{"executor":"constant-vus"},"peak":{"executor":"ramping-arrival-rate"}}}`, true},
+		{"iteration-based", `{"scenarios":{"soak":{"executor":"constant-vus"},"once":{"executor":"shared-iterations"}}}`, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var io InspectOutput
+			if err := json.Unmarshal([]byte(tc.input), &io); err != nil {
+				t.Fatalf("failed to unmarshal %s: %v", tc.input, err)
+			}
+			if got := io.DurationBased(); got != tc.expected {
+				t.Errorf("InspectOutput.DurationBased() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEnvironment_Format(t *testing.T) {
+	t.Parallel()
+
+	var trData TestRunData
+	if err := json.Unmarshal([]byte(`{"k8s_load_zones_config": {"environment": {"TOKEN": "secret", "API_URL": "https://example.com"}}}`), &trData); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "map[API_URL=*** TOKEN=***]"
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		if got := fmt.Sprintf(format, trData.Environment); got != expected {
+			t.Errorf("%s: expected %s, got %s", format, expected, got)
+		}
+	}
+
+	if got := fmt.Sprintf("%+v", trData); strings.Contains(got, "secret") || !strings.Contains(got, expected) {
+		t.Errorf("expected masked environment in %s", got)
+	}
+}
+
+func TestTestRunStatus_Ended(t *testing.T) {
+	t.Parallel()
+
+	for status, ended := range map[cloudapi.RunStatus]bool{
+		cloudapi.RunStatusCreated:     false,
+		cloudapi.RunStatusRunning:     false,
+		cloudapi.RunStatusFinished:    true,
+		cloudapi.RunStatusTimedOut:    true,
+		cloudapi.RunStatusAbortedUser: true,
+	} {
+		if TestRunStatus(status).Ended() != ended {
+			t.Errorf("Ended of run status %d should be %v", status, ended)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package cloud
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader is the header with HMAC-SHA256 signature of the timestamp
+	// and the body of a webhook request, in the format `sha256=<hex>`.
+	SignatureHeader = "X-K6-Signature"
+
+	// TimestampHeader is the header with the time a webhook request was sent,
+	// in seconds since the epoch.
+	TimestampHeader = "X-K6-Timestamp"
+
+	// MaxNotificationAge is how far from the current time the timestamp of a
+	// webhook request may be. Older requests are rejected as replays.
+	MaxNotificationAge = 5 * time.Minute
+)
+
+var (
+	// ErrInvalidSignature means that a webhook request is not signed with the secret.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrStaleNotification means that a webhook request was sent too long ago,
+	// e.g. because it is replayed.
+	ErrStaleNotification = errors.New("stale notification")
+)
+
+// TestRunNotification is the body of a webhook request about a new PLZ test run.
+type TestRunNotification struct {
+	PLZName   string `json:"plz_name"`
+	TestRunID uint64 `json:"test_run_id"`
+}
+
+// Sign returns the signature for SignatureHeader of the body sent at the
+// timestamp of TimestampHeader: HMAC-SHA256 of `<timestamp>.<body>`.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseTestRunNotification verifies the signature of the body sent at the
+// timestamp, checks that the timestamp is within MaxNotificationAge of now
+// and parses the body.
+func ParseTestRunNotification(secret, body []byte, timestamp, signature string, now time.Time) (*TestRunNotification, error) {
+	if !strings.HasPrefix(signature, "sha256=") ||
+		!hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return nil, ErrInvalidSignature
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid timestamp %q", ErrStaleNotification, timestamp)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > MaxNotificationAge || age < -MaxNotificationAge {
+		return nil, fmt.Errorf("%w: sent at %s", ErrStaleNotification, time.Unix(seconds, 0).UTC().Format(time.RFC3339))
+	}
+
+	var n TestRunNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("invalid notification: %w", err)
+	}
+
+	if len(n.PLZName) == 0 || n.TestRunID == 0 {
+		return nil, errors.New("invalid notification: plz_name and test_run_id are required")
+	}
+
+	return &n, nil
+}
@@ -0,0 +1,43 @@
+package cloud
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTestRunNotification(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"plz_name":"my-plz","test_run_id":123}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	n, err := ParseTestRunNotification(secret, body, timestamp, Sign(secret, timestamp, body), now)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestRunNotification{PLZName: "my-plz", TestRunID: 123}, n)
+
+	_, err = ParseTestRunNotification(secret, body, timestamp, Sign([]byte("other"), timestamp, body), now)
+	assert.EqualError(t, err, "invalid signature")
+
+	_, err = ParseTestRunNotification(secret, body, timestamp, "", now)
+	assert.EqualError(t, err, "invalid signature")
+
+	// the timestamp is signed
+	later := strconv.FormatInt(now.Add(time.Hour).Unix(), 10)
+	_, err = ParseTestRunNotification(secret, body, later, Sign(secret, timestamp, body), now.Add(time.Hour))
+	assert.EqualError(t, err, "invalid signature")
+
+	// a replay is rejected once the notification is stale
+	_, err = ParseTestRunNotification(secret, body, timestamp, Sign(secret, timestamp, body), now.Add(MaxNotificationAge+time.Second))
+	assert.True(t, errors.Is(err, ErrStaleNotification), "unexpected error: %v", err)
+
+	_, err = ParseTestRunNotification(secret, body, "", Sign(secret, "", body), now)
+	assert.True(t, errors.Is(err, ErrStaleNotification), "unexpected error: %v", err)
+
+	body = []byte(`{"plz_name":"my-plz"}`)
+	_, err = ParseTestRunNotification(secret, body, timestamp, Sign(secret, timestamp, body), now)
+	assert.Error(t, err)
+}
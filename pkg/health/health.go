@@ -0,0 +1,121 @@
+// Package health contains health checks of the operator, served by the manager
+// on /healthz and /readyz, and the debug endpoint with their detailed state.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// DebugPath is the path of the debug endpoint on the metrics server.
+const DebugPath = "/debug/health"
+
+// Check is a health check of a component of the operator.
+type Check struct {
+	Name string
+	// Liveness marks checks whose failure can be fixed only by restarting
+	// the operator. All checks are readiness checks.
+	Liveness bool
+	Checker  healthz.Checker
+	// Details returns the state of the component for the debug endpoint. Optional.
+	Details func() any
+}
+
+// Registry keeps all health checks of the operator.
+// It is an http.Handler of the debug endpoint.
+type Registry struct {
+	mu     sync.RWMutex
+	checks []Check
+}
+
+// Add registers the check with the manager and in the registry.
+func (r *Registry) Add(mgr manager.Manager, check Check) error {
+	if err := mgr.AddReadyzCheck(check.Name, check.Checker); err != nil {
+		return err
+	}
+	if check.Liveness {
+		if err := mgr.AddHealthzCheck(check.Name, check.Checker); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, check)
+	return nil
+}
+
+type checkStatus struct {
+	Healthy  bool   `json:"healthy"`
+	Liveness bool   `json:"liveness"`
+	Error    string `json:"error,omitempty"`
+	Details  any    `json:"details,omitempty"`
+}
+
+// ServeHTTP runs all checks and responds with their state as JSON.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status := make(map[string]checkStatus, len(r.checks))
+	for _, check := range r.checks {
+		s := checkStatus{
+			Healthy:  true,
+			Liveness: check.Liveness,
+		}
+		if err := check.Checker(req); err != nil {
+			s.Healthy = false
+			s.Error = err.Error()
+		}
+		if check.Details != nil {
+			s.Details = check.Details()
+		}
+		status[check.Name] = s
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// CacheSynced fails until the informers of the manager's cache are synced.
+func CacheSynced(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), time.Second)
+		defer cancel()
+
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("cache is not synced")
+		}
+		return nil
+	}
+}
+
+// Cached runs the checker at most once per ttl and returns the last result
+// in between. It is meant for checks calling external services.
+func Cached(checker healthz.Checker, ttl time.Duration) healthz.Checker {
+	var (
+		mu      sync.Mutex
+		checked time.Time
+		lastErr error
+	)
+
+	return func(req *http.Request) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if time.Since(checked) >= ttl {
+			lastErr = checker(req)
+			checked = time.Now()
+		}
+		return lastErr
+	}
+}
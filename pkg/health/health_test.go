@@ -0,0 +1,56 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCached(t *testing.T) {
+	calls := 0
+	checker := Cached(func(_ *http.Request) error {
+		calls++
+		return errors.New("unreachable")
+	}, time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	assert.EqualError(t, checker(req), "unreachable")
+	assert.EqualError(t, checker(req), "unreachable")
+	assert.Equal(t, 1, calls)
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	r := &Registry{
+		checks: []Check{
+			{
+				Name:     "poller",
+				Liveness: true,
+				Checker:  func(_ *http.Request) error { return nil },
+				Details:  func() any { return map[string]bool{"polling": true} },
+			},
+			{
+				Name:    "cloud",
+				Checker: func(_ *http.Request) error { return errors.New("unreachable") },
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DebugPath, nil))
+
+	var status map[string]checkStatus
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+
+	assert.True(t, status["poller"].Healthy)
+	assert.True(t, status["poller"].Liveness)
+	assert.Equal(t, map[string]any{"polling": true}, status["poller"].Details)
+
+	assert.False(t, status["cloud"].Healthy)
+	assert.Equal(t, "unreachable", status["cloud"].Error)
+}
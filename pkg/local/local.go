@@ -0,0 +1,69 @@
+// Package local runs a TestRun with local k6 processes instead of runner
+// jobs, to iterate on distributed test behavior without a cluster.
+package local
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/jobs"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Load reads the first TestRun of the manifest.
+func Load(r io.Reader) (*v1alpha1.TestRun, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		k6 := &v1alpha1.TestRun{}
+		if err := decoder.Decode(k6); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("no TestRun in the manifest")
+			}
+			return nil, err
+		}
+		if k6.Kind == "TestRun" {
+			return k6, nil
+		}
+	}
+}
+
+// ScriptPath returns the local path of the script of the TestRun. ConfigMap
+// and VolumeClaim files are looked up next to the manifest, as is LocalFile
+// unless it exists at its path. Generated scripts must be converted beforehand.
+func ScriptPath(k6 *v1alpha1.TestRun, manifest string) (string, error) {
+	if k6.GeneratedScript() {
+		return "", errors.New("script generated from a HAR recording or an OpenAPI spec must be converted and set with -script")
+	}
+
+	script, err := k6.GetSpec().ParseScript()
+	if err != nil {
+		return "", err
+	}
+
+	if script.Type == "LocalFile" {
+		if _, err := os.Stat(script.FullName()); err == nil {
+			return script.FullName(), nil
+		}
+	}
+
+	return filepath.Join(filepath.Dir(manifest), script.Filename), nil
+}
+
+// Command returns arguments of `k6` for the runner with the index, listening
+// on the port. They are the arguments of the runner jobs of the TestRun,
+// except that runners always linger so that their results can be collected.
+func Command(k6 *v1alpha1.TestRun, index int, script string, port int) ([]string, error) {
+	args, _, err := jobs.NewRunnerArgs(k6, index, script, jobs.RunnerOptions{
+		Address: net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+		Linger:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{"run"}, args...), nil
+}
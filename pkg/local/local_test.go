@@ -0,0 +1,105 @@
+package local
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Load(t *testing.T) {
+	manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+---
+apiVersion: k6.io/v1alpha1
+kind: TestRun
+metadata:
+  name: k6-sample
+spec:
+  parallelism: 2
+  script:
+    configMap:
+      name: test
+      file: test.js
+`
+	k6, err := Load(strings.NewReader(manifest))
+	assert.NoError(t, err)
+	assert.Equal(t, "k6-sample", k6.Name)
+	assert.Equal(t, int32(2), k6.Spec.Parallelism)
+
+	_, err = Load(strings.NewReader("apiVersion: v1\nkind: ConfigMap\n"))
+	assert.Error(t, err)
+}
+
+func Test_ScriptPath(t *testing.T) {
+	k6 := &v1alpha1.TestRun{}
+	k6.Spec.Script.ConfigMap.Name = "test"
+	k6.Spec.Script.ConfigMap.File = "test.js"
+
+	path, err := ScriptPath(k6, "tests/testrun.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "tests/test.js", path)
+
+	k6.Spec.Script = v1alpha1.K6Script{LocalFile: "/does/not/exist/local.js"}
+	path, err = ScriptPath(k6, "tests/testrun.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "tests/local.js", path)
+
+	_, err = ScriptPath(&v1alpha1.TestRun{}, "tests/testrun.yaml")
+	assert.Error(t, err)
+
+	k6.Spec.Script = v1alpha1.K6Script{HAR: &v1alpha1.K6ScriptConversion{
+		ConfigMap: v1alpha1.K6Configmap{Name: "recordings"},
+	}}
+	_, err = ScriptPath(k6, "tests/testrun.yaml")
+	assert.Error(t, err)
+}
+
+func Test_Command(t *testing.T) {
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: v1alpha1.TestRunSpec{
+			Parallelism: 2,
+			Arguments:   "--out json=out.json",
+		},
+	}
+	v1alpha1.Initialize(k6)
+
+	command, err := Command(k6, 2, "test.js", 6566)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"run", "--quiet",
+		"--execution-segment=1/2:1", "--execution-segment-sequence=0,1/2,1",
+		"--out", "json=out.json",
+		"test.js", "--address=127.0.0.1:6566", "--paused",
+		"--tag", "instance_id=2", "--tag", "job_name=test-2",
+		"--linger",
+	}, command)
+}
+
+func Test_CommandPLZ(t *testing.T) {
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: v1alpha1.TestRunSpec{
+			Parallelism: 1,
+			TestRunID:   "123",
+			Quiet:       "false",
+			Paused:      "false",
+		},
+	}
+	v1alpha1.Initialize(k6)
+
+	command, err := Command(k6, 1, "test.js", 6565)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"run",
+		"test.js", "--address=127.0.0.1:6565",
+		"--tag", "instance_id=1", "--tag", "job_name=test-1",
+		"--no-setup", "--no-teardown", "--linger",
+	}, command)
+}
@@ -0,0 +1,414 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/grafana/k6-operator/pkg/report"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	"github.com/grafana/k6-operator/pkg/types"
+	"go.k6.io/k6/lib"
+)
+
+const (
+	// readyTimeout is how long runners are given to serve their REST API.
+	readyTimeout = time.Minute
+	// stopTimeout is how long runners are given to exit once interrupted.
+	stopTimeout = 10 * time.Second
+)
+
+// Options of a local run.
+type Options struct {
+	// File is the manifest of the TestRun.
+	File string
+	// Script overrides the path of the script of the TestRun.
+	Script string
+	// K6 is the k6 binary.
+	K6 string
+	// Port is the port of the REST API of the first runner; the other
+	// runners listen on the next ones.
+	Port int
+	// Interval between reports of the aggregated status.
+	Interval time.Duration
+	// Out receives the output of the runners and the reports.
+	Out io.Writer
+}
+
+// runner is a local k6 process in place of a runner job.
+type runner struct {
+	name     string
+	hostname string
+	cmd      *exec.Cmd
+	done     chan struct{}
+	err      error
+}
+
+func (r *runner) exited() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run executes the TestRun with local runners: it inspects the script,
+// launches paused runners, runs setup, starts them and runs teardown the
+// way the controller does, reporting the aggregated status meanwhile.
+// It returns the summary of the merged results of the runners.
+func Run(ctx context.Context, opts Options) (*report.Summary, error) {
+	f, err := os.Open(opts.File)
+	if err != nil {
+		return nil, err
+	}
+	k6, err := Load(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", opts.File, err)
+	}
+
+	v1alpha1.Initialize(k6)
+	if k6.GetSpec().Parallelism < 1 {
+		k6.GetSpec().Parallelism = 1
+	}
+
+	script := opts.Script
+	if len(script) == 0 {
+		if script, err = ScriptPath(k6, opts.File); err != nil {
+			return nil, err
+		}
+	}
+
+	out := &syncWriter{w: opts.Out}
+
+	inspectOutput, err := inspect(ctx, opts.K6, k6, script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect the test script: %w", err)
+	}
+	if int32(inspectOutput.MaxVUs) < k6.GetSpec().Parallelism {
+		return nil, fmt.Errorf("number of instances > number of VUs: maxVUs %d, parallelism %d",
+			inspectOutput.MaxVUs, k6.GetSpec().Parallelism)
+	}
+
+	runners, err := launch(k6, opts, script, out)
+	defer stop(runners)
+	if err != nil {
+		return nil, err
+	}
+
+	hostnames := make([]string, len(runners))
+	for i, r := range runners {
+		hostnames[i] = r.hostname
+	}
+
+	if err = waitReady(ctx, runners); err != nil {
+		return nil, err
+	}
+	out.Printf("%d/%d runners ready\n", len(runners), k6.GetSpec().Parallelism)
+
+	if v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) {
+		out.Printf("Invoking setup() on the first runner\n")
+		setupData, err := testrun.RunSetup(ctx, hostnames[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to invoke setup(): %w", err)
+		}
+		if err = testrun.SetSetupData(ctx, hostnames, setupData); err != nil {
+			return nil, fmt.Errorf("failed to send setup data: %w", err)
+		}
+	}
+
+	out.Printf("Starting %d runners\n", len(runners))
+	if err = testrun.SetPaused(ctx, hostnames, false); err != nil {
+		return nil, fmt.Errorf("failed to start runners: %w", err)
+	}
+
+	if err = watch(ctx, runners, opts.Interval, out); err != nil {
+		out.Printf("Stopping runners: %v\n", err)
+		stopCtx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+		defer cancel()
+		if err := testrun.StopRunners(stopCtx, alive(runners)); err != nil {
+			out.Printf("Failed to stop runners: %v\n", err)
+		}
+		ctx = stopCtx
+	} else if v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) {
+		out.Printf("Invoking teardown() on the first responsive runner\n")
+		if err := testrun.RunTeardown(ctx, alive(runners)); err != nil {
+			out.Printf("Failed to invoke teardown(): %v\n", err)
+		}
+	}
+
+	var results []*types.RunnerResults
+	for _, hostname := range alive(runners) {
+		result, err := testrun.GetResults(ctx, hostname)
+		if err != nil {
+			out.Printf("Failed to collect results of runner %s: %v\n", hostname, err)
+			continue
+		}
+		results = append(results, result)
+	}
+	if len(results) == 0 {
+		return nil, errors.New("no results were collected from the runners")
+	}
+
+	return report.NewSummary(report.Merge(results), inspectOutput.Thresholds), nil
+}
+
+// inspect archives the script with the arguments of the TestRun and
+// inspects the archive, as the initializer does.
+func inspect(ctx context.Context, k6Bin string, k6 *v1alpha1.TestRun, script string) (*cloud.InspectOutput, error) {
+	dir, err := os.MkdirTemp("", "k6-operator-local")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	archive := filepath.Join(dir, "archive.tar")
+	args := []string{"archive", script, "-O", archive}
+	args = append(args, strings.Fields(types.ParseCLI(k6.GetSpec().Arguments).ArchiveArgs)...)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, k6Bin, args...)
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	stderr.Reset()
+	cmd = exec.CommandContext(ctx, k6Bin, "inspect", "--execution-requirements", archive)
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var inspectOutput cloud.InspectOutput
+	if err = json.Unmarshal(stdout, &inspectOutput); err != nil {
+		return nil, err
+	}
+	return &inspectOutput, nil
+}
+
+// launch starts a k6 process per runner of the TestRun. The runners that
+// were started are returned even on error so that they can be stopped.
+func launch(k6 *v1alpha1.TestRun, opts Options, script string, out *syncWriter) ([]*runner, error) {
+	var runners []*runner
+
+	for i := 1; i <= int(k6.GetSpec().Parallelism); i++ {
+		port := opts.Port + i - 1
+		args, err := Command(k6, i, script, port)
+		if err != nil {
+			return runners, err
+		}
+
+		r := &runner{
+			name:     fmt.Sprintf("%s-%d", k6.NamespacedName().Name, i),
+			hostname: fmt.Sprintf("127.0.0.1:%d", port),
+			done:     make(chan struct{}),
+		}
+		r.cmd = exec.Command(opts.K6, args...)
+		r.cmd.Stdout = out.Prefixed(r.name)
+		r.cmd.Stderr = r.cmd.Stdout
+
+		out.Printf("Launching runner %s: %s %s\n", r.name, opts.K6, strings.Join(args, " "))
+		if err = r.cmd.Start(); err != nil {
+			return runners, fmt.Errorf("failed to launch runner %s: %w", r.name, err)
+		}
+		go func() {
+			r.err = r.cmd.Wait()
+			close(r.done)
+		}()
+
+		runners = append(runners, r)
+	}
+
+	return runners, nil
+}
+
+// waitReady waits until the REST API of every runner responds.
+func waitReady(ctx context.Context, runners []*runner) error {
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+
+	ready := make(map[string]bool)
+	for {
+		for _, r := range runners {
+			if ready[r.name] {
+				continue
+			}
+			if r.exited() {
+				return fmt.Errorf("runner %s exited: %v", r.name, r.err)
+			}
+			if _, err := testrun.GetStatus(ctx, r.hostname); err == nil {
+				ready[r.name] = true
+			}
+		}
+		if len(ready) == len(runners) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("runners are not ready: %w", ctx.Err())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// watch reports the aggregated status of the runners until all of them
+// have ended. It returns an error if ctx is done first.
+func watch(ctx context.Context, runners []*runner, interval time.Duration, out *syncWriter) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		var (
+			ended, vus, vusMax int64
+			statuses           = make(map[string]int)
+		)
+		for _, r := range runners {
+			if r.exited() {
+				ended++
+				statuses["exited"]++
+				continue
+			}
+			status, err := testrun.GetStatus(ctx, r.hostname)
+			if err != nil {
+				statuses["unreachable"]++
+				continue
+			}
+			if status.Status >= lib.ExecutionStatusEnded {
+				ended++
+			}
+			statuses[status.Status.String()]++
+			vus += status.VUs.Int64
+			vusMax += status.VUsMax.Int64
+		}
+
+		out.Printf("Status: %s; VUs: %d/%d\n", formatCounts(statuses), vus, vusMax)
+
+		if ended == int64(len(runners)) {
+			return nil
+		}
+	}
+}
+
+// alive returns hostnames of the runners whose process is still running.
+func alive(runners []*runner) []string {
+	var hostnames []string
+	for _, r := range runners {
+		if !r.exited() {
+			hostnames = append(hostnames, r.hostname)
+		}
+	}
+	return hostnames
+}
+
+// stop interrupts the runners and kills those that don't exit in time.
+func stop(runners []*runner) {
+	for _, r := range runners {
+		if !r.exited() {
+			_ = r.cmd.Process.Signal(os.Interrupt)
+		}
+	}
+	timeout := time.After(stopTimeout)
+	for _, r := range runners {
+		select {
+		case <-r.done:
+		case <-timeout:
+			_ = r.cmd.Process.Kill()
+			<-r.done
+		}
+	}
+}
+
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%d %s", counts[key], key)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PrintSummary writes the outcome of the test run.
+func PrintSummary(w io.Writer, summary *report.Summary) {
+	if summary.ThresholdsPassed {
+		fmt.Fprintln(w, "Thresholds passed")
+	} else {
+		fmt.Fprintln(w, "Thresholds failed:")
+		for _, threshold := range summary.FailedThresholds {
+			fmt.Fprintf(w, "  %s\n", threshold)
+		}
+	}
+
+	names := make([]string, 0, len(summary.Metrics))
+	for name := range summary.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s = %g\n", name, summary.Metrics[name])
+	}
+}
+
+// syncWriter serializes writes of the runners and of the reports, so that
+// their lines don't interleave.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Printf(format string, args ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.w, format, args...)
+}
+
+// Prefixed returns a writer that prefixes each line with the name.
+func (s *syncWriter) Prefixed(name string) io.Writer {
+	return &prefixWriter{out: s, prefix: "[" + name + "] "}
+}
+
+type prefixWriter struct {
+	out    *syncWriter
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		p.out.Printf("%s%s\n", p.prefix, p.buf[:i])
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
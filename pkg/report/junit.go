@@ -0,0 +1,192 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.k6.io/k6/metrics"
+)
+
+// JUnitKey is the key under which JUnit report is stored in the ConfigMap.
+const JUnitKey = "junit.xml"
+
+var thresholdRegexp = regexp.MustCompile(`^\s*([a-z]+(?:\(\s*[0-9.]+\s*\))?)\s*(<=|>=|===|==|!=|<|>)\s*(-?[0-9.]+)\s*$`)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+// NewJUnit generates a JUnit XML report with one test case per threshold
+// and one test case per check.
+func NewJUnit(name string, results *Results, thresholds map[string]*metrics.Thresholds) ([]byte, error) {
+	report := junitTestSuites{
+		Name: name,
+		Suites: []junitTestSuite{
+			thresholdsSuite(results, thresholds),
+			checksSuite(results),
+		},
+	}
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+	}
+
+	out, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), out...), nil
+}
+
+func thresholdsSuite(results *Results, thresholds map[string]*metrics.Thresholds) junitTestSuite {
+	suite := junitTestSuite{
+		Name: "thresholds",
+	}
+
+	// to have deterministic order in the report
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if thresholds[name] == nil {
+			continue
+		}
+
+		for _, threshold := range thresholds[name].Thresholds {
+			testCase := junitTestCase{
+				Name:      fmt.Sprintf("%s: %s", name, threshold.Source),
+				ClassName: name,
+			}
+
+			if passed, observed := evaluateThreshold(results.Metrics[name], threshold.Source); !passed {
+				testCase.Failure = &junitFailure{
+					Message: fmt.Sprintf("threshold %s of %s has failed, observed %s", threshold.Source, name, observed),
+					Type:    "threshold",
+					Content: observed,
+				}
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+			suite.Tests++
+		}
+	}
+
+	return suite
+}
+
+func checksSuite(results *Results) junitTestSuite {
+	suite := junitTestSuite{
+		Name: "checks",
+	}
+
+	for _, check := range results.Checks {
+		testCase := junitTestCase{
+			Name:      check.Name,
+			ClassName: groupClassName(check.Group),
+		}
+
+		if check.Fails > 0 {
+			observed := fmt.Sprintf("%d of %d checks failed", check.Fails, check.Passes+check.Fails)
+			testCase.Failure = &junitFailure{
+				Message: observed,
+				Type:    "check",
+				Content: observed,
+			}
+			suite.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+	}
+
+	return suite
+}
+
+// groupClassName converts a k6 group path, like `::group::subgroup`,
+// into a JUnit class name.
+func groupClassName(path string) string {
+	path = strings.TrimPrefix(path, "::")
+	if len(path) == 0 {
+		return "default"
+	}
+	return strings.ReplaceAll(path, "::", ".")
+}
+
+// evaluateThreshold evaluates the threshold expression against the merged metric
+// and returns whether the threshold has passed together with the observed value.
+// If the aggregation method is not available in the merged metric, the result
+// reported by the runners is used.
+func evaluateThreshold(metric *Metric, source string) (passed bool, observed string) {
+	if metric == nil {
+		// no samples were emitted for this metric
+		return true, "no samples"
+	}
+
+	match := thresholdRegexp.FindStringSubmatch(source)
+	if match == nil {
+		return !metric.Tainted, "n/a"
+	}
+
+	var (
+		key      = strings.ReplaceAll(match[1], " ", "")
+		operator = match[2]
+	)
+
+	lhs, ok := metric.Sample[key]
+	rhs, err := strconv.ParseFloat(match[3], 64)
+	if !ok || err != nil {
+		return !metric.Tainted, "n/a"
+	}
+
+	observed = fmt.Sprintf("%s=%s", key, strconv.FormatFloat(lhs, 'f', -1, 64))
+
+	switch operator {
+	case ">":
+		passed = lhs > rhs
+	case ">=":
+		passed = lhs >= rhs
+	case "<=":
+		passed = lhs <= rhs
+	case "<":
+		passed = lhs < rhs
+	case "==", "===":
+		passed = lhs == rhs
+	case "!=":
+		passed = lhs != rhs
+	}
+
+	return
+}
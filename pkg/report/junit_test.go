@@ -0,0 +1,148 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/grafana/k6-operator/pkg/types"
+	"github.com/stretchr/testify/assert"
+	k6api "go.k6.io/k6/api/v1"
+	"go.k6.io/k6/metrics"
+	"gopkg.in/guregu/null.v3"
+)
+
+func runnerResults(duration map[string]float64, reqs float64, passes, fails int64) *types.RunnerResults {
+	return &types.RunnerResults{
+		Metrics: []k6api.Metric{
+			{
+				Name:    "http_req_duration",
+				Type:    k6api.NullMetricType{Type: metrics.Trend, Valid: true},
+				Tainted: null.BoolFrom(false),
+				Sample:  duration,
+			},
+			{
+				Name:   "http_reqs",
+				Type:   k6api.NullMetricType{Type: metrics.Counter, Valid: true},
+				Sample: map[string]float64{"count": reqs, "rate": reqs / 10},
+			},
+		},
+		Groups: []k6api.Group{
+			{
+				Path: "",
+				Checks: []k6api.Check{{
+					Path:   "::status is 200",
+					Name:   "status is 200",
+					Passes: passes,
+					Fails:  fails,
+				}},
+			},
+			{
+				Path: "::login",
+				Checks: []k6api.Check{{
+					Path:   "::login::logged in",
+					Name:   "logged in",
+					Passes: passes,
+				}},
+			},
+		},
+	}
+}
+
+func TestMerge(t *testing.T) {
+	results := Merge([]*types.RunnerResults{
+		runnerResults(map[string]float64{"min": 10, "max": 300, "avg": 100, "p(95)": 250}, 100, 100, 0),
+		runnerResults(map[string]float64{"min": 5, "max": 200, "avg": 50, "p(95)": 150}, 50, 45, 5),
+	})
+
+	// averages are weighted by http_reqs of the runners, percentiles are an upper bound
+	duration := results.Metrics["http_req_duration"].Sample
+	assert.InDelta(t, 83.333, duration["avg"], 0.001)
+	assert.Equal(t, 5.0, duration["min"])
+	assert.Equal(t, 300.0, duration["max"])
+	assert.Equal(t, 250.0, duration["p(95)"])
+	assert.Equal(t, map[string]float64{"count": 150, "rate": 15}, results.Metrics["http_reqs"].Sample)
+
+	assert.Equal(t, []*Check{
+		{Group: "", Name: "status is 200", Passes: 145, Fails: 5},
+		{Group: "::login", Name: "logged in", Passes: 145},
+	}, results.Checks)
+}
+
+func TestMergeWeightedRates(t *testing.T) {
+	rate := func(name string, value float64) k6api.Metric {
+		return k6api.Metric{
+			Name:   name,
+			Type:   k6api.NullMetricType{Type: metrics.Rate, Valid: true},
+			Sample: map[string]float64{"rate": value},
+		}
+	}
+
+	first := runnerResults(nil, 300, 90, 10)
+	first.Metrics = append(first.Metrics, rate("http_req_failed", 0.1), rate("checks", 0.9))
+	second := runnerResults(nil, 100, 20, 80)
+	second.Metrics = append(second.Metrics, rate("http_req_failed", 0.5), rate("checks", 0.2))
+
+	results := Merge([]*types.RunnerResults{first, second})
+
+	// 300 requests with 10% failed and 100 requests with 50% failed
+	assert.InDelta(t, 0.2, results.Metrics["http_req_failed"].Sample["rate"], 0.0001)
+	// 190 checks of the groups with 90% passed and 120 checks with 20% passed
+	assert.InDelta(t, 0.629, results.Metrics["checks"].Sample["rate"], 0.001)
+}
+
+func TestNewJUnit(t *testing.T) {
+	results := Merge([]*types.RunnerResults{
+		runnerResults(map[string]float64{"min": 10, "max": 300, "avg": 100, "p(95)": 250}, 100, 100, 0),
+		runnerResults(map[string]float64{"min": 5, "max": 200, "avg": 50, "p(95)": 150}, 50, 45, 5),
+	})
+
+	var thresholds map[string]*metrics.Thresholds
+	err := json.Unmarshal([]byte(`{
+		"http_req_duration": ["p(95)<200", "avg < 100"],
+		"http_reqs": ["count>100"]
+	}`), &thresholds)
+	assert.NoError(t, err)
+
+	out, err := NewJUnit("test", results, thresholds)
+	assert.NoError(t, err)
+
+	report := string(out)
+
+	assert.True(t, strings.HasPrefix(report, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, report, `<testsuites name="test" tests="5" failures="2">`)
+	assert.Contains(t, report, `<testsuite name="thresholds" tests="3" failures="1">`)
+	assert.Contains(t, report, `<failure message="threshold p(95)&lt;200 of http_req_duration has failed, observed p(95)=250" type="threshold">p(95)=250</failure>`)
+	assert.Contains(t, report, `<testcase name="http_req_duration: avg &lt; 100" classname="http_req_duration"></testcase>`)
+	assert.Contains(t, report, `<testcase name="http_reqs: count&gt;100" classname="http_reqs"></testcase>`)
+	assert.Contains(t, report, `<testsuite name="checks" tests="2" failures="1">`)
+	assert.Contains(t, report, `<failure message="5 of 150 checks failed" type="check">5 of 150 checks failed</failure>`)
+	assert.Contains(t, report, `<testcase name="logged in" classname="login"></testcase>`)
+}
+
+func TestNewSummary(t *testing.T) {
+	results := Merge([]*types.RunnerResults{
+		runnerResults(map[string]float64{"min": 10, "max": 300, "avg": 100, "p(95)": 250}, 100, 100, 0),
+		runnerResults(map[string]float64{"min": 5, "max": 200, "avg": 50, "p(95)": 150}, 50, 45, 5),
+	})
+
+	var thresholds map[string]*metrics.Thresholds
+	err := json.Unmarshal([]byte(`{
+		"http_req_duration": ["p(95)<200", "avg < 100"],
+		"http_reqs": ["count>100"]
+	}`), &thresholds)
+	assert.NoError(t, err)
+
+	summary := NewSummary(results, thresholds)
+
+	assert.False(t, summary.ThresholdsPassed)
+	assert.Equal(t, []string{"http_req_duration: p(95)<200"}, summary.FailedThresholds)
+	assert.Equal(t, map[string]float64{
+		"http_req_duration.min":   5,
+		"http_req_duration.max":   300,
+		"http_req_duration.avg":   250.0 / 3, // weighted by http_reqs
+		"http_req_duration.p(95)": 250,
+		"http_reqs.count":         150,
+		"http_reqs.rate":          15,
+	}, summary.Metrics)
+}
@@ -0,0 +1,163 @@
+package report
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/grafana/k6-operator/pkg/types"
+	"go.k6.io/k6/metrics"
+)
+
+// Metric is a metric merged from all runners.
+type Metric struct {
+	Name    string
+	Type    metrics.MetricType
+	Tainted bool
+	Sample  map[string]float64
+}
+
+// Check is a check merged from all runners.
+type Check struct {
+	Group  string
+	Name   string
+	Passes int64
+	Fails  int64
+}
+
+// Results are the results of the whole test run, merged from all runners.
+type Results struct {
+	Metrics map[string]*Metric
+	Checks  []*Check
+}
+
+// Merge combines partial results of the runners into the results of the test run.
+//
+// k6 REST API exposes only aggregated values of metrics so the merge is exact
+// for counters and checks, while the rest is approximated:
+//   - rates and averages are averaged across runners, weighted by the number
+//     of samples of each runner (see sampleWeight),
+//   - percentiles, medians, maximums and gauges take the highest value of all
+//     runners: percentiles are thus an upper bound of the percentiles of the
+//     whole test run,
+//   - minimums take the lowest value of all runners.
+func Merge(runners []*types.RunnerResults) *Results {
+	var (
+		results = &Results{
+			Metrics: make(map[string]*Metric),
+		}
+		checks  = make(map[string]*Check)
+		weights = make(map[string]float64)
+	)
+
+	for _, runner := range runners {
+		for _, m := range runner.Metrics {
+			merged, ok := results.Metrics[m.Name]
+			if !ok {
+				merged = &Metric{
+					Name:   m.Name,
+					Type:   m.Type.Type,
+					Sample: make(map[string]float64, len(m.Sample)),
+				}
+				results.Metrics[m.Name] = merged
+			}
+
+			merged.Tainted = merged.Tainted || m.Tainted.Bool
+
+			weight := sampleWeight(runner, m.Name)
+			for key, value := range m.Sample {
+				existing, exists := merged.Sample[key]
+				if !exists {
+					merged.Sample[key] = value
+					continue
+				}
+
+				merged.Sample[key] = mergeValue(merged.Type, key, existing, value, weights[m.Name], weight)
+			}
+			weights[m.Name] += weight
+		}
+
+		for _, g := range runner.Groups {
+			for _, c := range g.Checks {
+				merged, ok := checks[c.Path]
+				if !ok {
+					merged = &Check{
+						Group: g.Path,
+						Name:  c.Name,
+					}
+					checks[c.Path] = merged
+					results.Checks = append(results.Checks, merged)
+				}
+
+				merged.Passes += c.Passes
+				merged.Fails += c.Fails
+			}
+		}
+	}
+
+	// to have deterministic order in the report
+	sort.Slice(results.Checks, func(i, j int) bool {
+		if results.Checks[i].Group != results.Checks[j].Group {
+			return results.Checks[i].Group < results.Checks[j].Group
+		}
+		return results.Checks[i].Name < results.Checks[j].Name
+	})
+
+	return results
+}
+
+// sampleWeight returns the number of samples of the metric collected by the
+// runner. REST API doesn't expose it for rates and trends, so it is taken
+// from the counter of the same samples: http_reqs for HTTP metrics, checks
+// of the groups for checks and iterations for the rest. Runners without such
+// a counter weigh 1.
+func sampleWeight(runner *types.RunnerResults, name string) float64 {
+	if name == "checks" {
+		var total int64
+		for _, g := range runner.Groups {
+			for _, c := range g.Checks {
+				total += c.Passes + c.Fails
+			}
+		}
+		return float64(total)
+	}
+
+	counter := "iterations"
+	if strings.HasPrefix(name, "http_req_") {
+		counter = "http_reqs"
+	}
+	for _, m := range runner.Metrics {
+		if m.Name == counter {
+			return m.Sample["count"]
+		}
+	}
+	return 1
+}
+
+// mergeValue merges a new value of a runner into the existing one;
+// merged is the weight of the runners merged so far and weight is the
+// weight of the new runner.
+func mergeValue(t metrics.MetricType, key string, existing, value, merged, weight float64) float64 {
+	switch {
+	case t == metrics.Counter:
+		return existing + value
+
+	case key == "min":
+		if value < existing {
+			return value
+		}
+		return existing
+
+	case key == "avg" || t == metrics.Rate:
+		if merged+weight == 0 {
+			return (existing + value) / 2
+		}
+		// weighted running average
+		return existing + (value-existing)*weight/(merged+weight)
+
+	default:
+		if value > existing {
+			return value
+		}
+		return existing
+	}
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"go.k6.io/k6/metrics"
+)
+
+// keyMetrics are included in the summary whenever they are emitted by the test.
+var keyMetrics = map[string][]string{
+	"http_req_duration": {"avg", "p(90)", "p(95)", "max"},
+	"http_req_failed":   {"rate"},
+	"http_reqs":         {"count", "rate"},
+	"iterations":        {"count"},
+	"checks":            {"rate"},
+}
+
+// Summary is the outcome of the test run.
+type Summary struct {
+	ThresholdsPassed bool
+	// FailedThresholds are formatted as `metric: expression`.
+	FailedThresholds []string
+	// Metrics are keyed by `metric.aggregation`.
+	Metrics map[string]float64
+}
+
+// NewSummary evaluates the thresholds against the merged results and
+// picks values of the key metrics and of the metrics with thresholds.
+func NewSummary(results *Results, thresholds map[string]*metrics.Thresholds) *Summary {
+	summary := &Summary{
+		ThresholdsPassed: true,
+		Metrics:          make(map[string]float64),
+	}
+
+	// to have deterministic order of failed thresholds
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if thresholds[name] == nil {
+			continue
+		}
+
+		for _, threshold := range thresholds[name].Thresholds {
+			if passed, _ := evaluateThreshold(results.Metrics[name], threshold.Source); !passed {
+				summary.ThresholdsPassed = false
+				summary.FailedThresholds = append(summary.FailedThresholds, fmt.Sprintf("%s: %s", name, threshold.Source))
+			}
+		}
+
+		if metric, ok := results.Metrics[name]; ok {
+			for key, value := range metric.Sample {
+				summary.Metrics[name+"."+key] = value
+			}
+		}
+	}
+
+	for name, keys := range keyMetrics {
+		metric, ok := results.Metrics[name]
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			if value, ok := metric.Sample[key]; ok {
+				summary.Metrics[name+"."+key] = value
+			}
+		}
+	}
+
+	return summary
+}
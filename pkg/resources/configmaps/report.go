@@ -0,0 +1,29 @@
+package configmaps
+
+import (
+	"fmt"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewReportConfigMap builds a ConfigMap to store the reports of the test run.
+func NewReportConfigMap(k6 *v1alpha1.TestRun, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ReportName(k6),
+			Namespace: k6.NamespacedName().Namespace,
+			Labels: map[string]string{
+				"app":   "k6",
+				"k6_cr": k6.NamespacedName().Name,
+			},
+		},
+		Data: data,
+	}
+}
+
+// ReportName returns the name of the ConfigMap with the reports of the test run.
+func ReportName(k6 *v1alpha1.TestRun) string {
+	return fmt.Sprintf("%s-report", k6.NamespacedName().Name)
+}
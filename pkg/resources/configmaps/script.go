@@ -0,0 +1,65 @@
+package configmaps
+
+import (
+	"fmt"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewScriptConfigMap builds a copy of the script ConfigMap from another
+// namespace in the namespace of the test run.
+func NewScriptConfigMap(k6 *v1alpha1.TestRun, source *corev1.ConfigMap) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ScriptName(k6),
+			Namespace: k6.NamespacedName().Namespace,
+			Labels: map[string]string{
+				"app":   "k6",
+				"k6_cr": k6.NamespacedName().Name,
+			},
+			Annotations: map[string]string{
+				"k6.io/script-source": fmt.Sprintf("%s/%s", source.Namespace, source.Name),
+			},
+		},
+		Data:       source.Data,
+		BinaryData: source.BinaryData,
+	}
+}
+
+// ScriptName returns the name of the ConfigMap with the copy of the script.
+func ScriptName(k6 *v1alpha1.TestRun) string {
+	return fmt.Sprintf("%s-script", k6.NamespacedName().Name)
+}
+
+// GeneratedScriptKey is the key of the generated script in its ConfigMap.
+const GeneratedScriptKey = "test.js"
+
+// MaxGeneratedScriptSize is the size of the largest script that fits into
+// a ConfigMap: keys and values of a ConfigMap can't exceed 1 MiB in total.
+const MaxGeneratedScriptSize = corev1.MaxSecretSize - len(GeneratedScriptKey)
+
+// NewGeneratedScriptConfigMap builds a ConfigMap to store the script
+// generated by the initializer.
+func NewGeneratedScriptConfigMap(k6 *v1alpha1.TestRun, script string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GeneratedScriptName(k6),
+			Namespace: k6.NamespacedName().Namespace,
+			Labels: map[string]string{
+				"app":   "k6",
+				"k6_cr": k6.NamespacedName().Name,
+			},
+		},
+		Data: map[string]string{
+			GeneratedScriptKey: script,
+		},
+	}
+}
+
+// GeneratedScriptName returns the name of the ConfigMap with the script
+// generated by the initializer.
+func GeneratedScriptName(k6 *v1alpha1.TestRun) string {
+	return fmt.Sprintf("%s-generated-script", k6.NamespacedName().Name)
+}
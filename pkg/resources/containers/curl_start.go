@@ -12,6 +12,13 @@ import (
 	resource "k8s.io/apimachinery/pkg/api/resource"
 )
 
+// StartTimingMarker starts the line the start container logs after each start
+// request, with the duration of the request and the time it completed, both
+// in seconds:
+//
+//	k6-start host=10.0.0.1 code=200 total=0.004123 end=1718035200.123456789
+const StartTimingMarker = "k6-start"
+
 // NewStartContainer is used to get a template for a new k6 starting curl container.
 func NewStartContainer(hostnames []string, image string, imagePullPolicy corev1.PullPolicy, command []string, env []corev1.EnvVar, securityContext corev1.SecurityContext) corev1.Container {
 	req, _ := json.Marshal(
@@ -27,7 +34,9 @@ func NewStartContainer(hostnames []string, image string, imagePullPolicy corev1.
 
 	var parts []string
 	for _, hostname := range hostnames {
-		parts = append(parts, fmt.Sprintf("curl --retry 3 -X PATCH -H 'Content-Type: application/json' http://%s/v1/status -d '%s'", net.JoinHostPort(hostname, "6565"), req))
+		timing := fmt.Sprintf("%s host=%s code=%%{http_code} total=%%{time_total}", StartTimingMarker, hostname)
+		// the time of completion is appended once curl has exited; errors of curl go to stderr on their own lines
+		parts = append(parts, fmt.Sprintf("t=$(curl --retry 3 -sS -o /dev/null -w '%s' -X PATCH -H 'Content-Type: application/json' http://%s/v1/status -d '%s'); echo \"$t end=$(date +%%s.%%N)\"", timing, net.JoinHostPort(hostname, "6565"), req))
 	}
 
 	return corev1.Container{
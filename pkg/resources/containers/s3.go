@@ -8,11 +8,15 @@ import (
 )
 
 // NewS3InitContainer is used to download a script archive from S3.
+// A failed download fails the container, with the error of curl as
+// its termination message.
 func NewS3InitContainer(uri, image string, volumeMount corev1.VolumeMount) v1alpha1.InitContainer {
+	download := fmt.Sprintf(`out=$(curl -sS -f -X GET -L '%s' -o /test/archive.tar 2>&1) || { echo "$out" | tee /dev/termination-log; exit 1; }; ls -l /test`, uri)
+
 	return v1alpha1.InitContainer{
 		Name:         "archive-download",
 		Image:        image,
-		Command:      []string{"sh", "-c", fmt.Sprintf("curl -X GET -L '%s' > /test/archive.tar ; ls -l /test", uri)},
+		Command:      []string{"sh", "-c", download},
 		VolumeMounts: []corev1.VolumeMount{volumeMount},
 	}
 }
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Builder creates the jobs and services of a TestRun. Projects embedding
+// the TestRun controller can wrap DefaultBuilder to adjust the objects,
+// e.g. to add sidecars or labels required by their platform.
+type Builder interface {
+	NewInitializerJob(k6 *v1alpha1.TestRun, argLine string) (*batchv1.Job, error)
+	NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job, error)
+	NewRunnerService(k6 *v1alpha1.TestRun, index int) (*corev1.Service, error)
+	NewStarterJob(k6 *v1alpha1.TestRun, hostnames []string) *batchv1.Job
+	NewStopJob(k6 *v1alpha1.TestRun, hostnames []string) *batchv1.Job
+}
+
+// DefaultBuilder is the Builder of the operator.
+type DefaultBuilder struct{}
+
+var _ Builder = DefaultBuilder{}
+
+func (DefaultBuilder) NewInitializerJob(k6 *v1alpha1.TestRun, argLine string) (*batchv1.Job, error) {
+	return NewInitializerJob(k6, argLine)
+}
+
+func (DefaultBuilder) NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job, error) {
+	return NewRunnerJob(k6, index, token)
+}
+
+func (DefaultBuilder) NewRunnerService(k6 *v1alpha1.TestRun, index int) (*corev1.Service, error) {
+	return NewRunnerService(k6, index)
+}
+
+func (DefaultBuilder) NewStarterJob(k6 *v1alpha1.TestRun, hostnames []string) *batchv1.Job {
+	return NewStarterJob(k6, hostnames)
+}
+
+func (DefaultBuilder) NewStopJob(k6 *v1alpha1.TestRun, hostnames []string) *batchv1.Job {
+	return NewStopJob(k6, hostnames)
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/podgroups"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// applyGang assigns the runner pod to the PodGroup of the TestRun and its
+// gang scheduler, if gang scheduling is configured.
+func applyGang(k6 *v1alpha1.TestRun, template *corev1.PodTemplateSpec) {
+	scheduler := podgroups.Scheduler(k6)
+	if len(scheduler) == 0 {
+		return
+	}
+
+	template.Spec.SchedulerName = podgroups.SchedulerName(k6)
+
+	// labels and annotations may be shared with the job
+	if scheduler == v1alpha1.GangVolcano {
+		template.Annotations = withEntry(template.Annotations, podgroups.VolcanoAnnotation, podgroups.Name(k6))
+	} else {
+		template.Labels = withEntry(template.Labels, podgroups.CoschedulingLabel, podgroups.Name(k6))
+	}
+}
+
+// withEntry returns a copy of m with the key set to the value.
+func withEntry(m map[string]string, key, value string) map[string]string {
+	copied := make(map[string]string, len(m)+1)
+	for k, v := range m {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/podgroups"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewRunnerJobGangScheduling(t *testing.T) {
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Script: v1alpha1.K6Script{
+				ConfigMap: v1alpha1.K6Configmap{
+					Name: "test",
+					File: "test.js",
+				},
+			},
+			GangScheduling: &v1alpha1.GangScheduling{},
+		},
+	}
+
+	job, err := NewRunnerJob(k6, 1, "")
+	if err != nil {
+		t.Fatalf("NewRunnerJob errored, got: %v", err)
+	}
+
+	if name := job.Spec.Template.Spec.SchedulerName; name != "scheduler-plugins-scheduler" {
+		t.Errorf("unexpected scheduler name %q", name)
+	}
+	if group := job.Spec.Template.Labels[podgroups.CoschedulingLabel]; group != "test-runners" {
+		t.Errorf("expected pod group label test-runners, got %q", group)
+	}
+	if _, ok := job.Labels[podgroups.CoschedulingLabel]; ok {
+		t.Errorf("expected no pod group label on the runner job")
+	}
+
+	k6.Spec.GangScheduling.Scheduler = v1alpha1.GangVolcano
+	job, _ = NewRunnerJob(k6, 1, "")
+
+	if group := job.Spec.Template.Annotations[podgroups.VolcanoAnnotation]; group != "test-runners" {
+		t.Errorf("expected pod group annotation test-runners, got %q", group)
+	}
+	if name := job.Spec.Template.Spec.SchedulerName; name != "volcano" {
+		t.Errorf("unexpected scheduler name %q", name)
+	}
+}
@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"fmt"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ScriptGeneratorContainer is the init container of the initializer that
+	// generates the script. Its logs are the generated script and, once it
+	// succeeds, its termination message is the size of the script in bytes.
+	ScriptGeneratorContainer = "script-generator"
+
+	scriptSourceVolume = "k6-script-source"
+	scriptSourcePath   = "/source/"
+
+	defaultHARFile     = "recording.har"
+	defaultOpenAPIFile = "openapi.yaml"
+)
+
+// newConversionCommand returns the shell command that converts the source
+// of the TestRun into the script.
+func newConversionCommand(k6 *v1alpha1.TestRun, script *types.Script) string {
+	if har := k6.GetSpec().Script.HAR; har != nil {
+		file := defaultHARFile
+		if har.ConfigMap.File != "" {
+			file = har.ConfigMap.File
+		}
+		return withOptions(fmt.Sprintf("har-to-k6 %s%s -o %s",
+			scriptSourcePath, file, script.FullName()), har.Options)
+	}
+
+	openapi := k6.GetSpec().Script.OpenAPI
+	file := defaultOpenAPIFile
+	if openapi.ConfigMap.File != "" {
+		file = openapi.ConfigMap.File
+	}
+	// the k6 generator writes script.js to the output directory
+	return withOptions(fmt.Sprintf("openapi-generator-cli generate -g k6 -i %s%s -o /tmp/openapi",
+		scriptSourcePath, file), openapi.Options) +
+		fmt.Sprintf(" && cp /tmp/openapi/script.js %s", script.FullName())
+}
+
+func withOptions(command, options string) string {
+	if len(options) == 0 {
+		return command
+	}
+	return command + " " + options
+}
+
+// newScriptGenerator returns the init container that generates the script
+// of the initializer, together with the volumes of the script: the source
+// ConfigMap and an emptyDir in place of the ConfigMap of the generated script,
+// which doesn't exist yet.
+func newScriptGenerator(k6 *v1alpha1.TestRun, script *types.Script, image string, profile v1alpha1.SecurityProfile) (corev1.Container, []corev1.Volume) {
+	volumes := []corev1.Volume{
+		{
+			Name: "k6-test-volume",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: scriptSourceVolume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: k6.GetSpec().Script.Conversion().ConfigMap.Name,
+					},
+				},
+			},
+		},
+	}
+
+	volumeMounts := append(script.VolumeMount(), corev1.VolumeMount{
+		Name:      scriptSourceVolume,
+		MountPath: scriptSourcePath,
+		ReadOnly:  true,
+	})
+	volumeMounts = newTmpVolumeMount(profile, volumeMounts)
+
+	container := corev1.Container{
+		Name:            ScriptGeneratorContainer,
+		Image:           image,
+		ImagePullPolicy: k6.GetSpec().Initializer.ImagePullPolicy,
+		Command: []string{"sh", "-c", fmt.Sprintf(
+			// Only the script is printed so that the operator can store it
+			// from the logs; the output of the converter is kept for errors.
+			// The size of the script lets the operator detect truncated logs.
+			"(%s) > /tmp/k6-generator.log 2>&1 || { cat /tmp/k6-generator.log > %s; exit 1; }; wc -c < %s > %s; cat %s",
+			newConversionCommand(k6, script), corev1.TerminationMessagePathDefault,
+			script.FullName(), corev1.TerminationMessagePathDefault, script.FullName())},
+		Env:             k6.GetSpec().Initializer.Env,
+		EnvFrom:         k6.GetSpec().Initializer.EnvFrom,
+		Resources:       k6.GetSpec().Initializer.Resources,
+		VolumeMounts:    volumeMounts,
+		SecurityContext: newContainerSecurityContext(profile, k6.GetSpec().Initializer.ContainerSecurityContext),
+	}
+
+	return container, volumes
+}
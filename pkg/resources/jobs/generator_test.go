@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newGeneratedTestRun(script v1alpha1.K6Script) *v1alpha1.TestRun {
+	return &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Script: script,
+		},
+	}
+}
+
+func TestParseScriptGenerated(t *testing.T) {
+	k6 := newGeneratedTestRun(v1alpha1.K6Script{
+		HAR: &v1alpha1.K6ScriptConversion{
+			ConfigMap: v1alpha1.K6Configmap{Name: "recordings"},
+		},
+	})
+
+	script, err := parseScript(k6)
+	if err != nil {
+		t.Fatalf("parseScript returned unexpected error: %v", err)
+	}
+	if script.Name != "test-generated-script" || script.Type != "ConfigMap" || script.FullName() != "/test/test.js" {
+		t.Errorf("parseScript returned unexpected script: %+v", script)
+	}
+
+	k6.Spec.Script.HAR.ConfigMap.Namespace = "library"
+	if _, err = parseScript(k6); err == nil {
+		t.Errorf("parseScript accepted a ConfigMap from another namespace")
+	}
+}
+
+func TestNewConversionCommand(t *testing.T) {
+	k6 := newGeneratedTestRun(v1alpha1.K6Script{
+		HAR: &v1alpha1.K6ScriptConversion{
+			ConfigMap: v1alpha1.K6Configmap{Name: "recordings", File: "login.har"},
+			Options:   "--only example.com",
+		},
+	})
+	script, _ := parseScript(k6)
+
+	expected := "har-to-k6 /source/login.har -o /test/test.js --only example.com"
+	if command := newConversionCommand(k6, script); command != expected {
+		t.Errorf("unexpected HAR conversion command: %s", command)
+	}
+
+	k6 = newGeneratedTestRun(v1alpha1.K6Script{
+		OpenAPI: &v1alpha1.K6ScriptConversion{
+			ConfigMap: v1alpha1.K6Configmap{Name: "specs"},
+		},
+	})
+	script, _ = parseScript(k6)
+
+	expected = "openapi-generator-cli generate -g k6 -i /source/openapi.yaml -o /tmp/openapi && cp /tmp/openapi/script.js /test/test.js"
+	if command := newConversionCommand(k6, script); command != expected {
+		t.Errorf("unexpected OpenAPI conversion command: %s", command)
+	}
+}
+
+func TestNewInitializerJobGeneratedScript(t *testing.T) {
+	k6 := newGeneratedTestRun(v1alpha1.K6Script{
+		HAR: &v1alpha1.K6ScriptConversion{
+			ConfigMap: v1alpha1.K6Configmap{Name: "recordings"},
+		},
+	})
+
+	job, err := NewInitializerJob(k6, "")
+	if err != nil {
+		t.Fatalf("NewInitializerJob returned unexpected error: %v", err)
+	}
+
+	spec := job.Spec.Template.Spec
+	if len(spec.InitContainers) != 1 || spec.InitContainers[0].Name != ScriptGeneratorContainer {
+		t.Fatalf("expected a script generator init container, got %+v", spec.InitContainers)
+	}
+	if !strings.Contains(spec.InitContainers[0].Command[2], "har-to-k6 /source/recording.har") {
+		t.Errorf("unexpected command of the script generator: %s", spec.InitContainers[0].Command[2])
+	}
+	if !strings.Contains(spec.InitContainers[0].Command[2], "wc -c < /test/test.js > /dev/termination-log; cat /test/test.js") {
+		t.Errorf("script generator should report the size of the script: %s", spec.InitContainers[0].Command[2])
+	}
+
+	volumes := make(map[string]corev1.VolumeSource)
+	for _, v := range spec.Volumes {
+		volumes[v.Name] = v.VolumeSource
+	}
+	if volumes["k6-test-volume"].EmptyDir == nil {
+		t.Errorf("script volume of the initializer should be an emptyDir: %+v", volumes["k6-test-volume"])
+	}
+	if cm := volumes[scriptSourceVolume].ConfigMap; cm == nil || cm.Name != "recordings" {
+		t.Errorf("source volume of the initializer should be the ConfigMap: %+v", volumes[scriptSourceVolume])
+	}
+
+	if !strings.Contains(spec.Containers[0].Command[2], "k6 archive /test/test.js") {
+		t.Errorf("initializer should archive the generated script: %s", spec.Containers[0].Command[2])
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/grafana/k6-operator/pkg/resources/configmaps"
 	"github.com/grafana/k6-operator/pkg/types"
 
 	"github.com/grafana/k6-operator/api/v1alpha1"
@@ -17,6 +18,27 @@ func newLabels(name string) map[string]string {
 	}
 }
 
+// parseScript returns the script of the TestRun. A ConfigMap from
+// another namespace is replaced with its copy owned by the TestRun, and
+// a generated script is read from the ConfigMap it is stored in.
+func parseScript(k6 *v1alpha1.TestRun) (*types.Script, error) {
+	script, err := k6.GetSpec().ParseScript()
+	if err != nil {
+		return nil, err
+	}
+
+	if k6.CrossNamespaceScript() {
+		script.Name = configmaps.ScriptName(k6)
+	}
+
+	if k6.GeneratedScript() {
+		script.Name = configmaps.GeneratedScriptName(k6)
+		script.Filename = configmaps.GeneratedScriptKey
+	}
+
+	return script, nil
+}
+
 func newIstioCommand(istioEnabled string, inheritedCommands []string) ([]string, bool) {
 	istio := false
 	if istioEnabled != "" {
@@ -117,7 +139,7 @@ func newIstioEnvVar(istio v1alpha1.K6Scuttle, istioEnabled bool) []corev1.EnvVar
 }
 
 // TODO: Envoy variables are not passed to init containers
-func getInitContainers(pod *v1alpha1.Pod, script *types.Script) []corev1.Container {
+func getInitContainers(pod *v1alpha1.Pod, script *types.Script, profile v1alpha1.SecurityProfile) []corev1.Container {
 	var initContainers []corev1.Container
 
 	for i, k6InitContainer := range pod.InitContainers {
@@ -128,6 +150,7 @@ func getInitContainers(pod *v1alpha1.Pod, script *types.Script) []corev1.Contain
 		}
 
 		volumeMounts := append(script.VolumeMount(), k6InitContainer.VolumeMounts...)
+		volumeMounts = newTmpVolumeMount(profile, volumeMounts)
 
 		initContainer := corev1.Container{
 			Name:            name,
@@ -139,10 +162,117 @@ func getInitContainers(pod *v1alpha1.Pod, script *types.Script) []corev1.Contain
 			Env:             k6InitContainer.Env,
 			VolumeMounts:    volumeMounts,
 			ImagePullPolicy: pod.ImagePullPolicy,
-			SecurityContext: &pod.ContainerSecurityContext,
+			SecurityContext: newContainerSecurityContext(profile, pod.ContainerSecurityContext),
 		}
 		initContainers = append(initContainers, initContainer)
 	}
 
 	return initContainers
 }
+
+const (
+	// default user and group of k6 images
+	defaultUser int64 = 12345
+
+	tmpVolumeName = "k6-tmp"
+	tmpMountPath  = "/tmp"
+)
+
+// newPodSecurityContext returns the pod security context configured by the user.
+// With the restricted profile, fields that the user didn't set are hardened.
+func newPodSecurityContext(profile v1alpha1.SecurityProfile, sc corev1.PodSecurityContext) *corev1.PodSecurityContext {
+	if profile != v1alpha1.SecurityProfileRestricted {
+		return &sc
+	}
+
+	var (
+		nonRoot = true
+		user    = defaultUser
+	)
+
+	if sc.RunAsNonRoot == nil {
+		sc.RunAsNonRoot = &nonRoot
+	}
+	if sc.RunAsUser == nil {
+		// k6 images define user by name so it must be numeric here
+		// for kubelet to verify runAsNonRoot
+		sc.RunAsUser = &user
+	}
+	if sc.RunAsGroup == nil {
+		sc.RunAsGroup = &user
+	}
+	if sc.FSGroup == nil {
+		sc.FSGroup = &user
+	}
+	if sc.SeccompProfile == nil {
+		sc.SeccompProfile = &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		}
+	}
+
+	return &sc
+}
+
+// newContainerSecurityContext returns the container security context configured by the user.
+// With the restricted profile, fields that the user didn't set are hardened.
+func newContainerSecurityContext(profile v1alpha1.SecurityProfile, sc corev1.SecurityContext) *corev1.SecurityContext {
+	if profile != v1alpha1.SecurityProfileRestricted {
+		return &sc
+	}
+
+	var (
+		no  = false
+		yes = true
+	)
+
+	if sc.AllowPrivilegeEscalation == nil {
+		sc.AllowPrivilegeEscalation = &no
+	}
+	if sc.Privileged == nil {
+		sc.Privileged = &no
+	}
+	if sc.ReadOnlyRootFilesystem == nil {
+		sc.ReadOnlyRootFilesystem = &yes
+	}
+	if sc.Capabilities == nil {
+		sc.Capabilities = &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		}
+	}
+
+	return &sc
+}
+
+// newTmpVolume adds a writable emptyDir for /tmp with the restricted profile,
+// since the root filesystem is read-only then.
+func newTmpVolume(profile v1alpha1.SecurityProfile, volumes []corev1.Volume) []corev1.Volume {
+	if profile != v1alpha1.SecurityProfileRestricted {
+		return volumes
+	}
+
+	return append(volumes, corev1.Volume{
+		Name: tmpVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+}
+
+// newTmpVolumeMount mounts the volume from newTmpVolume at /tmp, unless
+// the user has mounted their own volume there.
+func newTmpVolumeMount(profile v1alpha1.SecurityProfile, volumeMounts []corev1.VolumeMount) []corev1.VolumeMount {
+	if profile != v1alpha1.SecurityProfileRestricted {
+		return volumeMounts
+	}
+
+	for _, vm := range volumeMounts {
+		if vm.MountPath == tmpMountPath {
+			return volumeMounts
+		}
+	}
+
+	return append(volumeMounts, corev1.VolumeMount{
+		Name:      tmpVolumeName,
+		MountPath: tmpMountPath,
+	})
+}
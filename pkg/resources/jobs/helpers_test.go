@@ -7,6 +7,7 @@ import (
 	"github.com/go-test/deep"
 	"github.com/grafana/k6-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNewLabels(t *testing.T) {
@@ -153,3 +154,118 @@ func TestNewIstioEnvVarFalseValues(t *testing.T) {
 		t.Errorf("new envVars were incorrect, got: %v, want: %v.", envVars, expectedOutcome)
 	}
 }
+
+func TestNewPodSecurityContextRestricted(t *testing.T) {
+	var (
+		nonRoot       = true
+		user    int64 = 12345
+		custom  int64 = 1000
+	)
+
+	expectedOutcome := &corev1.PodSecurityContext{
+		RunAsNonRoot: &nonRoot,
+		RunAsUser:    &custom,
+		RunAsGroup:   &user,
+		FSGroup:      &user,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+
+	sc := newPodSecurityContext(v1alpha1.SecurityProfileRestricted, corev1.PodSecurityContext{
+		RunAsUser: &custom,
+	})
+
+	if diff := deep.Equal(expectedOutcome, sc); diff != nil {
+		t.Errorf("newPodSecurityContext returned unexpected data, diff: %s", diff)
+	}
+
+	if diff := deep.Equal(&corev1.PodSecurityContext{}, newPodSecurityContext("", corev1.PodSecurityContext{})); diff != nil {
+		t.Errorf("newPodSecurityContext without profile returned unexpected data, diff: %s", diff)
+	}
+}
+
+func TestNewContainerSecurityContextRestricted(t *testing.T) {
+	var (
+		no  = false
+		yes = true
+	)
+
+	expectedOutcome := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &no,
+		Privileged:               &no,
+		ReadOnlyRootFilesystem:   &no,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+
+	sc := newContainerSecurityContext(v1alpha1.SecurityProfileRestricted, corev1.SecurityContext{
+		ReadOnlyRootFilesystem: &no,
+	})
+
+	if diff := deep.Equal(expectedOutcome, sc); diff != nil {
+		t.Errorf("newContainerSecurityContext returned unexpected data, diff: %s", diff)
+	}
+
+	expectedOutcome.ReadOnlyRootFilesystem = &yes
+	sc = newContainerSecurityContext(v1alpha1.SecurityProfileRestricted, corev1.SecurityContext{})
+
+	if diff := deep.Equal(expectedOutcome, sc); diff != nil {
+		t.Errorf("newContainerSecurityContext returned unexpected data, diff: %s", diff)
+	}
+}
+
+func TestNewTmpVolumeMount(t *testing.T) {
+	userMount := corev1.VolumeMount{Name: "my-tmp", MountPath: "/tmp"}
+
+	if diff := deep.Equal([]corev1.VolumeMount{userMount}, newTmpVolumeMount(v1alpha1.SecurityProfileRestricted, []corev1.VolumeMount{userMount})); diff != nil {
+		t.Errorf("newTmpVolumeMount overrode user's volume mount, diff: %s", diff)
+	}
+
+	expectedOutcome := []corev1.VolumeMount{{Name: "k6-tmp", MountPath: "/tmp"}}
+	if diff := deep.Equal(expectedOutcome, newTmpVolumeMount(v1alpha1.SecurityProfileRestricted, nil)); diff != nil {
+		t.Errorf("newTmpVolumeMount returned unexpected data, diff: %s", diff)
+	}
+
+	if mounts := newTmpVolumeMount("", nil); len(mounts) > 0 {
+		t.Errorf("newTmpVolumeMount without profile returned unexpected data: %v", mounts)
+	}
+}
+
+func TestParseScriptCrossNamespace(t *testing.T) {
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "team",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Script: v1alpha1.K6Script{
+				ConfigMap: v1alpha1.K6Configmap{
+					Name:      "shared",
+					File:      "test.js",
+					Namespace: "library",
+				},
+			},
+		},
+	}
+
+	script, err := parseScript(k6)
+	if err != nil {
+		t.Fatalf("parseScript returned unexpected error: %v", err)
+	}
+	if script.Name != "test-script" {
+		t.Errorf("parseScript returned unexpected ConfigMap: %s", script.Name)
+	}
+
+	// a reference to the namespace of the TestRun is a local ConfigMap
+	k6.Spec.Script.ConfigMap.Namespace = "team"
+
+	script, err = parseScript(k6)
+	if err != nil {
+		t.Fatalf("parseScript returned unexpected error: %v", err)
+	}
+	if script.Name != "shared" {
+		t.Errorf("parseScript returned unexpected ConfigMap: %s", script.Name)
+	}
+}
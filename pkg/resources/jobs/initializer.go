@@ -12,7 +12,7 @@ import (
 
 // NewInitializerJob builds a template used to initializefor creating a starter job
 func NewInitializerJob(k6 *v1alpha1.TestRun, argLine string) (*batchv1.Job, error) {
-	script, err := k6.GetSpec().ParseScript()
+	script, err := parseScript(k6)
 	if err != nil {
 		return nil, err
 	}
@@ -76,17 +76,32 @@ func NewInitializerJob(k6 *v1alpha1.TestRun, argLine string) (*batchv1.Job, erro
 		// printing JSON as usual. Then parse temp file only for errors, ignoring
 		// any other log messages.
 		// Related: https://github.com/grafana/k6-docs/issues/877
-		"mkdir -p $(dirname %s) && k6 archive %s -O %s %s 2> /tmp/k6logs && k6 inspect --execution-requirements %s 2> /tmp/k6logs ; ! cat /tmp/k6logs | grep 'level=error'",
+		//
+		// Errors are also written as termination message so that the operator
+		// can report them without reading the logs.
+		"mkdir -p $(dirname %s) && k6 archive %s -O %s %s 2> /tmp/k6logs && k6 inspect --execution-requirements %s 2> /tmp/k6logs ; ! (grep 'level=error' /tmp/k6logs > %s && cat %s)",
 		archiveName, scriptName, archiveName, argLine,
-		archiveName))
+		archiveName,
+		corev1.TerminationMessagePathDefault, corev1.TerminationMessagePathDefault))
 
 	env := append(newIstioEnvVar(k6.GetSpec().Scuttle, istioEnabled), k6.GetSpec().Initializer.Env...)
 
+	profile := k6.GetSpec().SecurityProfile
+
+	initContainers := getInitContainers(k6.GetSpec().Initializer, script, profile)
+
 	volumes := script.Volume()
+	if k6.GeneratedScript() {
+		var generator corev1.Container
+		generator, volumes = newScriptGenerator(k6, script, image, profile)
+		initContainers = append(initContainers, generator)
+	}
 	volumes = append(volumes, k6.GetSpec().Initializer.Volumes...)
+	volumes = newTmpVolume(profile, volumes)
 
 	volumeMounts := script.VolumeMount()
 	volumeMounts = append(volumeMounts, k6.GetSpec().Initializer.VolumeMounts...)
+	volumeMounts = newTmpVolumeMount(profile, volumeMounts)
 
 	var zero32 int32
 	job := &batchv1.Job{
@@ -110,10 +125,10 @@ func NewInitializerJob(k6 *v1alpha1.TestRun, argLine string) (*batchv1.Job, erro
 					NodeSelector:                 k6.GetSpec().Initializer.NodeSelector,
 					Tolerations:                  k6.GetSpec().Initializer.Tolerations,
 					TopologySpreadConstraints:    k6.GetSpec().Initializer.TopologySpreadConstraints,
-					SecurityContext:              &k6.GetSpec().Initializer.SecurityContext,
+					SecurityContext:              newPodSecurityContext(profile, k6.GetSpec().Initializer.SecurityContext),
 					RestartPolicy:                corev1.RestartPolicyNever,
 					ImagePullSecrets:             k6.GetSpec().Initializer.ImagePullSecrets,
-					InitContainers:               getInitContainers(k6.GetSpec().Initializer, script),
+					InitContainers:               initContainers,
 					Containers: []corev1.Container{
 						{
 							Image:           image,
@@ -125,7 +140,9 @@ func NewInitializerJob(k6 *v1alpha1.TestRun, argLine string) (*batchv1.Job, erro
 							VolumeMounts:    volumeMounts,
 							EnvFrom:         k6.GetSpec().Initializer.EnvFrom,
 							Ports:           ports,
-							SecurityContext: &k6.GetSpec().Initializer.ContainerSecurityContext,
+							SecurityContext: newContainerSecurityContext(profile, k6.GetSpec().Initializer.ContainerSecurityContext),
+							// if k6 fails before writing the errors, the end of logs is used instead
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						},
 					},
 					Volumes: volumes,
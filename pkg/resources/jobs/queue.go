@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// QueueLabel is the label with the name of the Kueue LocalQueue of a job.
+const QueueLabel = "kueue.x-k8s.io/queue-name"
+
+// applyQueue submits the runner job to the Kueue queue of the TestRun:
+// the job is created suspended and Kueue resumes it once it is admitted.
+func applyQueue(k6 *v1alpha1.TestRun, job *batchv1.Job) {
+	queue := k6.GetSpec().QueueName
+	if len(queue) == 0 {
+		return
+	}
+
+	// labels may be shared with the pod template
+	job.Labels = withEntry(job.Labels, QueueLabel, queue)
+
+	suspend := true
+	job.Spec.Suspend = &suspend
+}
+
+// Admitted returns the number of jobs which were admitted by Kueue,
+// i.e. resumed after being created suspended.
+func Admitted(jobs []batchv1.Job) (admitted int) {
+	for _, job := range jobs {
+		if job.Spec.Suspend == nil || !*job.Spec.Suspend {
+			admitted++
+		}
+	}
+	return
+}
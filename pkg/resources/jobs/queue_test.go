@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewRunnerJobQueueName(t *testing.T) {
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Script: v1alpha1.K6Script{
+				ConfigMap: v1alpha1.K6Configmap{
+					Name: "test",
+					File: "test.js",
+				},
+			},
+			QueueName: "load-tests",
+		},
+	}
+
+	job, err := NewRunnerJob(k6, 1, "")
+	if err != nil {
+		t.Fatalf("NewRunnerJob errored, got: %v", err)
+	}
+
+	if job.Spec.Suspend == nil || !*job.Spec.Suspend {
+		t.Errorf("expected runner job to be suspended")
+	}
+	if queue := job.Labels[QueueLabel]; queue != "load-tests" {
+		t.Errorf("expected queue label load-tests, got %q", queue)
+	}
+	if _, ok := job.Spec.Template.Labels[QueueLabel]; ok {
+		t.Errorf("expected no queue label on the runner pod")
+	}
+
+	k6.Spec.QueueName = ""
+	if job, _ = NewRunnerJob(k6, 1, ""); job.Spec.Suspend != nil {
+		t.Errorf("expected runner job without a queue not to be suspended")
+	}
+}
+
+func TestAdmitted(t *testing.T) {
+	suspended, resumed := true, false
+
+	jobs := []batchv1.Job{
+		{Spec: batchv1.JobSpec{Suspend: &suspended}},
+		{Spec: batchv1.JobSpec{Suspend: &resumed}},
+		{Spec: batchv1.JobSpec{}},
+	}
+
+	if admitted := Admitted(jobs); admitted != 2 {
+		t.Errorf("expected 2 admitted jobs, got %d", admitted)
+	}
+}
@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"fmt"
+	"maps"
 	"strconv"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -10,18 +11,32 @@ import (
 
 	"github.com/grafana/k6-operator/api/v1alpha1"
 	"github.com/grafana/k6-operator/pkg/cloud"
+	"github.com/grafana/k6-operator/pkg/resources/permissions"
 	"github.com/grafana/k6-operator/pkg/segmentation"
+	"github.com/grafana/k6-operator/pkg/testrun"
+	"github.com/grafana/k6-operator/pkg/types"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// NewRunnerJob creates a new k6 job from a CRD
-func NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job, error) {
-	name := fmt.Sprintf("%s-%d", k6.NamespacedName().Name, index)
-	postCommand := []string{"k6", "run"}
+// RunnerOptions are the arguments of a runner that differ between runner
+// jobs and runners started as local processes.
+type RunnerOptions struct {
+	// Address is where the runner serves its REST API.
+	Address string
+	// JSONLogs makes the runner log in JSON unless spec.arguments sets the
+	// log format, so that the operator can collect error messages.
+	JSONLogs bool
+	// Linger keeps the REST API of the runner up after the test ends even if
+	// the TestRun doesn't require it.
+	Linger bool
+}
 
-	command, istioEnabled := newIstioCommand(k6.GetSpec().Scuttle.Enabled, postCommand)
+// NewRunnerArgs returns the arguments of `k6 run` of the runner with the
+// index, running the script. It also tells whether the runner lingers.
+func NewRunnerArgs(k6 *v1alpha1.TestRun, index int, script string, opts RunnerOptions) (args []string, linger bool, err error) {
+	name := fmt.Sprintf("%s-%d", k6.NamespacedName().Name, index)
 
 	quiet := true
 	if k6.GetSpec().Quiet != "" {
@@ -29,34 +44,26 @@ func NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job,
 	}
 
 	if quiet {
-		command = append(command, "--quiet")
+		args = append(args, "--quiet")
 	}
 
 	if k6.GetSpec().Parallelism > 1 {
-		var args []string
-		var err error
-
-		if args, err = segmentation.NewCommandFragments(index, int(k6.GetSpec().Parallelism)); err != nil {
-			return nil, err
-
+		fragments, err := segmentation.NewCommandFragments(index, int(k6.GetSpec().Parallelism))
+		if err != nil {
+			return nil, false, err
 		}
-		command = append(command, args...)
-	}
-
-	script, err := k6.GetSpec().ParseScript()
-	if err != nil {
-		return nil, err
+		args = append(args, fragments...)
 	}
 
 	if k6.GetSpec().Arguments != "" {
-		args := strings.Split(k6.GetSpec().Arguments, " ")
-		command = append(command, args...)
+		args = append(args, strings.Split(k6.GetSpec().Arguments, " ")...)
 	}
 
-	command = append(
-		command,
-		script.FullName(),
-		"--address=0.0.0.0:6565")
+	args = append(args, script, fmt.Sprintf("--address=%s", opts.Address))
+
+	if opts.JSONLogs && !types.ParseCLI(k6.GetSpec().Arguments).HasLogFormat {
+		args = append(args, "--log-format", "json")
+	}
 
 	paused := true
 	if k6.GetSpec().Paused != "" {
@@ -64,18 +71,51 @@ func NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job,
 	}
 
 	if paused {
-		command = append(command, "--paused")
+		args = append(args, "--paused")
 	}
 
 	// Add an instance tag: in case metrics are stored, they need to be distinguished by instance
-	command = append(command, "--tag", fmt.Sprintf("instance_id=%d", index))
+	args = append(args, "--tag", fmt.Sprintf("instance_id=%d", index))
 
 	// Add an job tag: in case metrics are stored, they need to be distinguished by job
-	command = append(command, "--tag", fmt.Sprintf("job_name=%s", name))
+	args = append(args, "--tag", fmt.Sprintf("job_name=%s", name))
 
+	linger = opts.Linger
 	if v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) {
-		command = append(command, "--no-setup", "--no-teardown", "--linger")
+		args = append(args, "--no-setup", "--no-teardown")
+		linger = true
+	} else if k6.GetSpec().Report.JUnit {
+		// runners must stay available until the results are collected
+		linger = true
+	}
+
+	if linger {
+		args = append(args, "--linger")
+	}
+
+	return args, linger, nil
+}
+
+// NewRunnerJob creates a new k6 job from a CRD
+func NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job, error) {
+	name := fmt.Sprintf("%s-%d", k6.NamespacedName().Name, index)
+	postCommand := []string{"k6", "run"}
+
+	command, istioEnabled := newIstioCommand(k6.GetSpec().Scuttle.Enabled, postCommand)
+
+	script, err := parseScript(k6)
+	if err != nil {
+		return nil, err
+	}
+
+	args, linger, err := NewRunnerArgs(k6, index, script.FullName(), RunnerOptions{
+		Address:  "0.0.0.0:6565",
+		JSONLogs: true,
+	})
+	if err != nil {
+		return nil, err
 	}
+	command = append(command, args...)
 
 	command = script.UpdateCommand(command)
 
@@ -84,6 +124,11 @@ func NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job,
 		zero32 int32 = 0
 	)
 
+	gracePeriod := zero
+	if t := k6.GetSpec().Termination; t != nil && t.GracePeriodSeconds != nil {
+		gracePeriod = *t.GracePeriodSeconds
+	}
+
 	image := "ghcr.io/grafana/k6-operator:latest-runner"
 	if k6.GetSpec().Runner.Image != "" {
 		image = k6.GetSpec().Runner.Image
@@ -94,6 +139,13 @@ func NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job,
 		runnerAnnotations = k6.GetSpec().Runner.Metadata.Annotations
 	}
 
+	// the job is told apart from the pods, as the command can't be relied upon
+	jobAnnotations := maps.Clone(runnerAnnotations)
+	jobAnnotations[testrun.RunnerIndexAnnotation] = strconv.Itoa(index)
+	if linger {
+		jobAnnotations[testrun.LingerAnnotation] = "true"
+	}
+
 	runnerLabels := newLabels(k6.NamespacedName().Name)
 	runnerLabels["runner"] = "true"
 	if k6.GetSpec().Runner.Metadata.Labels != nil {
@@ -108,6 +160,10 @@ func NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job,
 	if k6.GetSpec().Runner.ServiceAccountName != "" {
 		serviceAccountName = k6.GetSpec().Runner.ServiceAccountName
 	}
+	// permissions are granted to the ServiceAccount created for the runners
+	if permissions.Enabled(k6) {
+		serviceAccountName = permissions.Name(k6)
+	}
 
 	automountServiceAccountToken := true
 	if k6.GetSpec().Runner.AutomountServiceAccountToken != "" {
@@ -143,18 +199,22 @@ func NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job,
 
 	env = append(env, k6.GetSpec().Runner.Env...)
 
+	profile := k6.GetSpec().SecurityProfile
+
 	volumes := script.Volume()
 	volumes = append(volumes, k6.GetSpec().Runner.Volumes...)
+	volumes = newTmpVolume(profile, volumes)
 
 	volumeMounts := script.VolumeMount()
 	volumeMounts = append(volumeMounts, k6.GetSpec().Runner.VolumeMounts...)
+	volumeMounts = newTmpVolumeMount(profile, volumeMounts)
 
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Namespace:   k6.NamespacedName().Namespace,
 			Labels:      runnerLabels,
-			Annotations: runnerAnnotations,
+			Annotations: jobAnnotations,
 		},
 		Spec: batchv1.JobSpec{
 			BackoffLimit: &zero32,
@@ -172,33 +232,33 @@ func NewRunnerJob(k6 *v1alpha1.TestRun, index int, token string) (*batchv1.Job,
 					NodeSelector:                 k6.GetSpec().Runner.NodeSelector,
 					Tolerations:                  k6.GetSpec().Runner.Tolerations,
 					TopologySpreadConstraints:    k6.GetSpec().Runner.TopologySpreadConstraints,
-					SecurityContext:              &k6.GetSpec().Runner.SecurityContext,
+					SecurityContext:              newPodSecurityContext(profile, k6.GetSpec().Runner.SecurityContext),
 					ImagePullSecrets:             k6.GetSpec().Runner.ImagePullSecrets,
-					InitContainers:               getInitContainers(&k6.GetSpec().Runner, script),
+					InitContainers:               getInitContainers(&k6.GetSpec().Runner, script, profile),
 					Containers: []corev1.Container{{
 						Image:           image,
 						ImagePullPolicy: k6.GetSpec().Runner.ImagePullPolicy,
 						Name:            "k6",
 						Command:         command,
 						Env:             env,
-						Resources:       k6.GetSpec().Runner.Resources,
+						Resources:       testrun.RunnerResources(k6),
 						VolumeMounts:    volumeMounts,
 						Ports:           ports,
 						EnvFrom:         k6.GetSpec().Runner.EnvFrom,
 						LivenessProbe:   generateProbe(k6.GetSpec().Runner.LivenessProbe),
 						ReadinessProbe:  generateProbe(k6.GetSpec().Runner.ReadinessProbe),
-						SecurityContext: &k6.GetSpec().Runner.ContainerSecurityContext,
+						SecurityContext: newContainerSecurityContext(profile, k6.GetSpec().Runner.ContainerSecurityContext),
 					}},
-					TerminationGracePeriodSeconds: &zero,
+					TerminationGracePeriodSeconds: &gracePeriod,
 					Volumes:                       volumes,
 				},
 			},
 		},
 	}
 
-	if k6.GetSpec().Separate {
-		job.Spec.Template.Spec.Affinity = newAntiAffinity()
-	}
+	applySpread(k6, &job.Spec.Template.Spec)
+	applyQueue(k6, job)
+	applyGang(k6, &job.Spec.Template)
 
 	return job, nil
 }
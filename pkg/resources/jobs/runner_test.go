@@ -9,9 +9,12 @@ import (
 
 	deep "github.com/go-test/deep"
 	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/testrun"
 	"github.com/grafana/k6-operator/pkg/types"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -295,7 +298,8 @@ func TestNewRunnerJob(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -321,7 +325,7 @@ func TestNewRunnerJob(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: corev1.PullNever,
 						Name:            "k6",
-						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
+						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
 						Env:             []corev1.EnvVar{},
 						Resources:       corev1.ResourceRequirements{},
 						VolumeMounts:    script.VolumeMount(),
@@ -428,7 +432,8 @@ func TestNewRunnerJobNoisy(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -454,7 +459,7 @@ func TestNewRunnerJobNoisy(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: "",
 						Name:            "k6",
-						Command:         []string{"k6", "run", "/test/test.js", "--address=0.0.0.0:6565", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
+						Command:         []string{"k6", "run", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
 						Env:             []corev1.EnvVar{},
 						Resources:       corev1.ResourceRequirements{},
 						VolumeMounts:    script.VolumeMount(),
@@ -543,7 +548,8 @@ func TestNewRunnerJobUnpaused(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -569,7 +575,7 @@ func TestNewRunnerJobUnpaused(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: "",
 						Name:            "k6",
-						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
+						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
 						Env:             []corev1.EnvVar{},
 						Resources:       corev1.ResourceRequirements{},
 						VolumeMounts:    script.VolumeMount(),
@@ -658,7 +664,8 @@ func TestNewRunnerJobArguments(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -684,7 +691,7 @@ func TestNewRunnerJobArguments(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: "",
 						Name:            "k6",
-						Command:         []string{"k6", "run", "--quiet", "--cool-thing", "/test/test.js", "--address=0.0.0.0:6565", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
+						Command:         []string{"k6", "run", "--quiet", "--cool-thing", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
 						Env:             []corev1.EnvVar{},
 						Resources:       corev1.ResourceRequirements{},
 						VolumeMounts:    script.VolumeMount(),
@@ -774,7 +781,8 @@ func TestNewRunnerJobServiceAccount(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -800,7 +808,7 @@ func TestNewRunnerJobServiceAccount(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: "",
 						Name:            "k6",
-						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
+						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
 						Env:             []corev1.EnvVar{},
 						Resources:       corev1.ResourceRequirements{},
 						VolumeMounts:    script.VolumeMount(),
@@ -891,7 +899,8 @@ func TestNewRunnerJobIstio(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -917,7 +926,7 @@ func TestNewRunnerJobIstio(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: "",
 						Name:            "k6",
-						Command:         []string{"scuttle", "k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
+						Command:         []string{"scuttle", "k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
 						Env: []corev1.EnvVar{
 							{
 								Name:  "ENVOY_ADMIN_API",
@@ -1020,7 +1029,8 @@ func TestNewRunnerJobCloud(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -1046,7 +1056,7 @@ func TestNewRunnerJobCloud(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: "",
 						Name:            "k6",
-						Command:         []string{"k6", "run", "--quiet", "--out", "cloud", "/test/test.js", "--address=0.0.0.0:6565", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
+						Command:         []string{"k6", "run", "--quiet", "--out", "cloud", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
 						Env: append(aggregationEnvVars,
 							corev1.EnvVar{
 								Name:  "K6_CLOUD_PUSH_REF_ID",
@@ -1146,7 +1156,8 @@ func TestNewRunnerJobLocalFile(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -1172,7 +1183,7 @@ func TestNewRunnerJobLocalFile(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: "",
 						Name:            "k6",
-						Command:         []string{"sh", "-c", "if [ ! -f /test/test.js ]; then echo \"LocalFile not found exiting...\"; exit 1; fi;\nk6 run --quiet /test/test.js --address=0.0.0.0:6565 --paused --tag instance_id=1 --tag job_name=test-1"},
+						Command:         []string{"sh", "-c", "if [ ! -f /test/test.js ]; then echo \"LocalFile not found exiting...\"; exit 1; fi;\nk6 run --quiet /test/test.js --address=0.0.0.0:6565 --log-format json --paused --tag instance_id=1 --tag job_name=test-1"},
 						Env:             []corev1.EnvVar{},
 						Resources:       corev1.ResourceRequirements{},
 						VolumeMounts:    script.VolumeMount(),
@@ -1260,7 +1271,8 @@ func TestNewRunnerJobWithInitContainer(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -1305,7 +1317,7 @@ func TestNewRunnerJobWithInitContainer(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: corev1.PullNever,
 						Name:            "k6",
-						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
+						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
 						Env:             []corev1.EnvVar{},
 						Resources:       corev1.ResourceRequirements{},
 						VolumeMounts:    script.VolumeMount(),
@@ -1439,7 +1451,8 @@ func TestNewRunnerJobWithVolume(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -1484,7 +1497,7 @@ func TestNewRunnerJobWithVolume(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: corev1.PullNever,
 						Name:            "k6",
-						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
+						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1"},
 						Env:             []corev1.EnvVar{},
 						Resources:       corev1.ResourceRequirements{},
 						VolumeMounts:    expectedVolumeMounts,
@@ -1628,7 +1641,9 @@ func TestNewRunnerJobPLZTestRun(t *testing.T) {
 			Namespace: "test",
 			Labels:    expectedLabels,
 			Annotations: map[string]string{
-				"awesomeAnnotation": "dope",
+				"k6.io/runner-index": "1",
+				"awesomeAnnotation":  "dope",
+				"k6.io/linger":       "true",
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -1654,7 +1669,7 @@ func TestNewRunnerJobPLZTestRun(t *testing.T) {
 						Image:           "ghcr.io/grafana/k6-operator:latest-runner",
 						ImagePullPolicy: corev1.PullNever,
 						Name:            "k6",
-						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1", "--no-setup", "--no-teardown", "--linger"},
+						Command:         []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1", "--no-setup", "--no-teardown", "--linger"},
 						Env:             []corev1.EnvVar{},
 						Resources:       corev1.ResourceRequirements{},
 						VolumeMounts:    script.VolumeMount(),
@@ -1746,3 +1761,160 @@ func TestNewRunnerJobPLZTestRun(t *testing.T) {
 		t.Errorf("NewRunnerJob returned unexpected data, diff: %s", diff)
 	}
 }
+
+func TestNewRunnerJobWithReport(t *testing.T) {
+	expectedCommand := []string{"k6", "run", "--quiet", "/test/test.js", "--address=0.0.0.0:6565", "--log-format", "json", "--paused", "--tag", "instance_id=1", "--tag", "job_name=test-1", "--linger"}
+
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Script: v1alpha1.K6Script{
+				ConfigMap: v1alpha1.K6Configmap{
+					Name: "test",
+					File: "test.js",
+				},
+			},
+			Report: v1alpha1.Report{
+				JUnit: true,
+			},
+		},
+	}
+
+	job, err := NewRunnerJob(k6, 1, "")
+	if err != nil {
+		t.Errorf("NewRunnerJob errored, got: %v", err)
+	}
+	if diff := deep.Equal(job.Spec.Template.Spec.Containers[0].Command, expectedCommand); diff != nil {
+		t.Errorf("NewRunnerJob returned unexpected command, diff: %s", diff)
+	}
+	if !testrun.Lingering(job) {
+		t.Errorf("runner job with report should be lingering, annotations: %v", job.Annotations)
+	}
+	if _, ok := job.Spec.Template.Annotations[testrun.LingerAnnotation]; ok {
+		t.Errorf("linger annotation should be set only on the job, pod annotations: %v", job.Spec.Template.Annotations)
+	}
+
+	// the command of a LocalFile script is wrapped in a shell
+	k6.Spec.Script = v1alpha1.K6Script{LocalFile: "/test/test.js"}
+
+	job, err = NewRunnerJob(k6, 1, "")
+	if err != nil {
+		t.Errorf("NewRunnerJob errored, got: %v", err)
+	}
+	if command := job.Spec.Template.Spec.Containers[0].Command; command[0] != "sh" || command[1] != "-c" {
+		t.Errorf("NewRunnerJob returned unexpected command for LocalFile: %v", command)
+	}
+	if !testrun.Lingering(job) {
+		t.Errorf("runner job of a LocalFile script with report should be lingering, annotations: %v", job.Annotations)
+	}
+}
+
+func TestNewRunnerJobPermissions(t *testing.T) {
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Script: v1alpha1.K6Script{
+				ConfigMap: v1alpha1.K6Configmap{
+					Name: "test",
+					File: "test.js",
+				},
+			},
+			Runner: v1alpha1.Pod{
+				ServiceAccountName: "ignored",
+				Permissions: []rbacv1.PolicyRule{{
+					APIGroups: []string{""},
+					Resources: []string{"pods"},
+					Verbs:     []string{"get"},
+				}},
+			},
+		},
+	}
+
+	job, err := NewRunnerJob(k6, 1, "")
+	if err != nil {
+		t.Errorf("NewRunnerJob errored, got: %v", err)
+	}
+	if sa := job.Spec.Template.Spec.ServiceAccountName; sa != "test-runner" {
+		t.Errorf("expected ServiceAccount of runner permissions, got %s", sa)
+	}
+}
+
+func TestNewRunnerJobGracePeriod(t *testing.T) {
+	var gracePeriod int64 = 45
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Script: v1alpha1.K6Script{
+				ConfigMap: v1alpha1.K6Configmap{
+					Name: "test",
+					File: "test.js",
+				},
+			},
+			Termination: &v1alpha1.Termination{
+				GracePeriodSeconds: &gracePeriod,
+			},
+		},
+	}
+
+	job, err := NewRunnerJob(k6, 1, "")
+	if err != nil {
+		t.Errorf("NewRunnerJob errored, got: %v", err)
+	}
+	if p := job.Spec.Template.Spec.TerminationGracePeriodSeconds; p == nil || *p != gracePeriod {
+		t.Errorf("expected termination grace period of %d, got %v", gracePeriod, p)
+	}
+}
+
+func TestNewRunnerJobAppliedResources(t *testing.T) {
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Script: v1alpha1.K6Script{
+				ConfigMap: v1alpha1.K6Configmap{
+					Name: "test",
+					File: "test.js",
+				},
+			},
+			Runner: v1alpha1.Pod{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				},
+			},
+		},
+	}
+
+	job, err := NewRunnerJob(k6, 1, "")
+	if err != nil {
+		t.Errorf("NewRunnerJob errored, got: %v", err)
+	}
+	if cpu := job.Spec.Template.Spec.Containers[0].Resources.Requests.Cpu().String(); cpu != "4" {
+		t.Errorf("expected CPU request of the spec, got %s", cpu)
+	}
+
+	// applied resources are used by all runner jobs, e.g. replacements
+	k6.Status.ResourceUsage = &v1alpha1.ResourceUsage{
+		AppliedFrom: "previous",
+		Applied: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		},
+	}
+	job, err = NewRunnerJob(k6, 2, "")
+	if err != nil {
+		t.Errorf("NewRunnerJob errored, got: %v", err)
+	}
+	if cpu := job.Spec.Template.Spec.Containers[0].Resources.Requests.Cpu().String(); cpu != "500m" {
+		t.Errorf("expected applied CPU request, got %s", cpu)
+	}
+}
@@ -0,0 +1,184 @@
+package jobs
+
+import (
+	"fmt"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var topologyKeys = map[string]string{
+	"":         corev1.LabelHostname,
+	"hostname": corev1.LabelHostname,
+	"zone":     corev1.LabelTopologyZone,
+}
+
+// newSpread returns the spread of runners configured in the TestRun.
+// Deprecated `separate` is a required spread across hostnames, though its
+// runners are kept apart from the runners of all test runs.
+// It returns nil if runners are not supposed to be spread.
+func newSpread(k6 *v1alpha1.TestRun) *v1alpha1.Spread {
+	spread := k6.GetSpec().Spread
+	if spread == nil {
+		if !k6.GetSpec().Separate {
+			return nil
+		}
+		spread = &v1alpha1.Spread{}
+	}
+
+	resolved := *spread
+	if key, ok := topologyKeys[resolved.TopologyKey]; ok {
+		resolved.TopologyKey = key
+	}
+	if len(resolved.Mode) == 0 {
+		resolved.Mode = v1alpha1.SpreadRequired
+	}
+
+	return &resolved
+}
+
+// applySpread adds scheduling constraints of the spread to the runner pod:
+// a topology spread constraint if max skew is set and pod anti-affinity otherwise.
+func applySpread(k6 *v1alpha1.TestRun, podSpec *corev1.PodSpec) {
+	spread := newSpread(k6)
+	if spread == nil {
+		return
+	}
+
+	if spread.MaxSkew > 0 {
+		whenUnsatisfiable := corev1.DoNotSchedule
+		if spread.Mode == v1alpha1.SpreadPreferred {
+			whenUnsatisfiable = corev1.ScheduleAnyway
+		}
+
+		selector := newLabels(k6.NamespacedName().Name)
+		selector["runner"] = "true"
+
+		// user-defined constraints must not be modified
+		constraints := make([]corev1.TopologySpreadConstraint, len(podSpec.TopologySpreadConstraints), len(podSpec.TopologySpreadConstraints)+1)
+		copy(constraints, podSpec.TopologySpreadConstraints)
+
+		podSpec.TopologySpreadConstraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           spread.MaxSkew,
+			TopologyKey:       spread.TopologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+		})
+		return
+	}
+
+	// deprecated `separate` keeps runners apart from the runners of all test
+	// runs, as it always did
+	antiAffinity := newAntiAffinity()
+	if k6.GetSpec().Spread != nil {
+		antiAffinity = newSpreadAntiAffinity(k6.NamespacedName().Name, spread)
+	}
+
+	// user-defined affinity must be preserved
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = antiAffinity
+		return
+	}
+
+	podSpec.Affinity = podSpec.Affinity.DeepCopy()
+	if podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity.PodAntiAffinity = antiAffinity.PodAntiAffinity
+		return
+	}
+
+	podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+		podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		antiAffinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+	podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		antiAffinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
+}
+
+// newSpreadAntiAffinity allows at most one runner of the test run per topology domain.
+func newSpreadAntiAffinity(name string, spread *v1alpha1.Spread) *corev1.Affinity {
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      "app",
+					Operator: "In",
+					Values: []string{
+						"k6",
+					},
+				},
+				{
+					Key:      "k6_cr",
+					Operator: "In",
+					Values: []string{
+						name,
+					},
+				},
+				{
+					Key:      "runner",
+					Operator: "In",
+					Values: []string{
+						"true",
+					},
+				},
+			},
+		},
+		TopologyKey: spread.TopologyKey,
+	}
+
+	if spread.Mode == v1alpha1.SpreadPreferred {
+		return &corev1.Affinity{
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					{
+						Weight:          100,
+						PodAffinityTerm: term,
+					},
+				},
+			},
+		}
+	}
+
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+		},
+	}
+}
+
+// CheckSpread verifies that the spread of runners can be satisfied by the given nodes.
+// Only schedulable nodes matching the runner's node selector are taken into account.
+// Preferred spread is always satisfiable, as it is best effort.
+func CheckSpread(k6 *v1alpha1.TestRun, nodes []corev1.Node) error {
+	spread := newSpread(k6)
+	if spread == nil || spread.Mode == v1alpha1.SpreadPreferred {
+		return nil
+	}
+
+	selector := labels.SelectorFromSet(k6.GetSpec().Runner.NodeSelector)
+
+	domains := make(map[string]struct{})
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if domain, ok := node.Labels[spread.TopologyKey]; ok {
+			domains[domain] = struct{}{}
+		}
+	}
+
+	if len(domains) == 0 {
+		return fmt.Errorf("no schedulable node has label %s: runners will stay pending", spread.TopologyKey)
+	}
+
+	// anti-affinity allows only one runner per domain
+	if spread.MaxSkew == 0 && int32(len(domains)) < k6.GetSpec().Parallelism {
+		return fmt.Errorf("there are %d domains of %s for %d runners: %d runners will stay pending; consider setting maxSkew or preferred mode",
+			len(domains), spread.TopologyKey, k6.GetSpec().Parallelism, k6.GetSpec().Parallelism-int32(len(domains)))
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package jobs
+
+import (
+	"testing"
+
+	deep "github.com/go-test/deep"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newSpreadTestRun(spread *v1alpha1.Spread, separate bool) *v1alpha1.TestRun {
+	return &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Parallelism: 3,
+			Separate:    separate,
+			Spread:      spread,
+		},
+	}
+}
+
+func TestNewSpreadAntiAffinity(t *testing.T) {
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      "app",
+					Operator: "In",
+					Values: []string{
+						"k6",
+					},
+				},
+				{
+					Key:      "k6_cr",
+					Operator: "In",
+					Values: []string{
+						"test",
+					},
+				},
+				{
+					Key:      "runner",
+					Operator: "In",
+					Values: []string{
+						"true",
+					},
+				},
+			},
+		},
+		TopologyKey: "kubernetes.io/hostname",
+	}
+
+	expectedOutcome := &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+		},
+	}
+
+	antiAffinity := newSpreadAntiAffinity("test", &v1alpha1.Spread{
+		TopologyKey: "kubernetes.io/hostname",
+		Mode:        v1alpha1.SpreadRequired,
+	})
+
+	if diff := deep.Equal(expectedOutcome, antiAffinity); diff != nil {
+		t.Errorf("newSpreadAntiAffinity returned unexpected data, diff: %s", diff)
+	}
+
+	expectedOutcome = &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+				Weight:          100,
+				PodAffinityTerm: term,
+			}},
+		},
+	}
+
+	antiAffinity = newSpreadAntiAffinity("test", &v1alpha1.Spread{
+		TopologyKey: "kubernetes.io/hostname",
+		Mode:        v1alpha1.SpreadPreferred,
+	})
+
+	if diff := deep.Equal(expectedOutcome, antiAffinity); diff != nil {
+		t.Errorf("newSpreadAntiAffinity returned unexpected data, diff: %s", diff)
+	}
+}
+
+func TestNewSpread(t *testing.T) {
+	tests := []struct {
+		name     string
+		k6       *v1alpha1.TestRun
+		expected *v1alpha1.Spread
+	}{
+		{
+			name:     "no spread",
+			k6:       newSpreadTestRun(nil, false),
+			expected: nil,
+		},
+		{
+			name: "separate",
+			k6:   newSpreadTestRun(nil, true),
+			expected: &v1alpha1.Spread{
+				TopologyKey: "kubernetes.io/hostname",
+				Mode:        v1alpha1.SpreadRequired,
+			},
+		},
+		{
+			name: "zone shortcut",
+			k6:   newSpreadTestRun(&v1alpha1.Spread{TopologyKey: "zone", Mode: v1alpha1.SpreadPreferred, MaxSkew: 2}, false),
+			expected: &v1alpha1.Spread{
+				TopologyKey: "topology.kubernetes.io/zone",
+				Mode:        v1alpha1.SpreadPreferred,
+				MaxSkew:     2,
+			},
+		},
+		{
+			name: "custom key",
+			k6:   newSpreadTestRun(&v1alpha1.Spread{TopologyKey: "example.com/rack"}, true),
+			expected: &v1alpha1.Spread{
+				TopologyKey: "example.com/rack",
+				Mode:        v1alpha1.SpreadRequired,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := deep.Equal(test.expected, newSpread(test.k6)); diff != nil {
+				t.Errorf("newSpread returned unexpected data, diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestApplySpreadMaxSkew(t *testing.T) {
+	k6 := newSpreadTestRun(&v1alpha1.Spread{TopologyKey: "zone", MaxSkew: 1}, false)
+
+	userConstraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       "example.com/rack",
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+	}
+	podSpec := &corev1.PodSpec{
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{userConstraint},
+	}
+
+	applySpread(k6, podSpec)
+
+	expectedOutcome := []corev1.TopologySpreadConstraint{
+		userConstraint,
+		{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":    "k6",
+					"k6_cr":  "test",
+					"runner": "true",
+				},
+			},
+		},
+	}
+
+	if diff := deep.Equal(expectedOutcome, podSpec.TopologySpreadConstraints); diff != nil {
+		t.Errorf("applySpread returned unexpected data, diff: %s", diff)
+	}
+	if podSpec.Affinity != nil {
+		t.Errorf("applySpread with max skew set unexpected affinity: %v", podSpec.Affinity)
+	}
+}
+
+func TestApplySpreadSeparate(t *testing.T) {
+	podSpec := &corev1.PodSpec{}
+	applySpread(newSpreadTestRun(nil, true), podSpec)
+
+	// runners of deprecated `separate` repel the runners of all test runs
+	if diff := deep.Equal(newAntiAffinity(), podSpec.Affinity); diff != nil {
+		t.Errorf("applySpread with separate returned unexpected affinity, diff: %s", diff)
+	}
+
+	podSpec = &corev1.PodSpec{}
+	applySpread(newSpreadTestRun(&v1alpha1.Spread{}, true), podSpec)
+
+	if diff := deep.Equal(newSpreadAntiAffinity("test", newSpread(newSpreadTestRun(&v1alpha1.Spread{}, false))), podSpec.Affinity); diff != nil {
+		t.Errorf("applySpread with spread returned unexpected affinity, diff: %s", diff)
+	}
+}
+
+func TestApplySpreadPreservesAffinity(t *testing.T) {
+	k6 := newSpreadTestRun(&v1alpha1.Spread{}, false)
+	k6.Spec.Runner.Affinity = &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{},
+	}
+
+	podSpec := &corev1.PodSpec{
+		Affinity: k6.Spec.Runner.Affinity,
+	}
+
+	applySpread(k6, podSpec)
+
+	if podSpec.Affinity.NodeAffinity == nil {
+		t.Errorf("applySpread dropped user-defined node affinity")
+	}
+	if podSpec.Affinity.PodAntiAffinity == nil || len(podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 1 {
+		t.Errorf("applySpread returned unexpected pod anti-affinity: %v", podSpec.Affinity.PodAntiAffinity)
+	}
+	if k6.Spec.Runner.Affinity.PodAntiAffinity != nil {
+		t.Errorf("applySpread modified affinity of TestRun")
+	}
+}
+
+func TestCheckSpread(t *testing.T) {
+	node := func(name, zone string, unschedulable bool) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"kubernetes.io/hostname":      name,
+					"topology.kubernetes.io/zone": zone,
+				},
+			},
+			Spec: corev1.NodeSpec{
+				Unschedulable: unschedulable,
+			},
+		}
+	}
+
+	nodes := []corev1.Node{
+		node("node-1", "zone-a", false),
+		node("node-2", "zone-b", false),
+		node("node-3", "zone-b", false),
+		node("node-4", "zone-c", true),
+	}
+
+	tests := []struct {
+		name    string
+		spread  *v1alpha1.Spread
+		wantErr bool
+	}{
+		{"enough hostnames", &v1alpha1.Spread{TopologyKey: "hostname"}, false},
+		{"not enough zones", &v1alpha1.Spread{TopologyKey: "zone"}, true},
+		{"not enough zones but preferred", &v1alpha1.Spread{TopologyKey: "zone", Mode: v1alpha1.SpreadPreferred}, false},
+		{"not enough zones but max skew", &v1alpha1.Spread{TopologyKey: "zone", MaxSkew: 1}, false},
+		{"missing label", &v1alpha1.Spread{TopologyKey: "example.com/rack", MaxSkew: 1}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckSpread(newSpreadTestRun(test.spread, false), nodes)
+			if (err != nil) != test.wantErr {
+				t.Errorf("CheckSpread returned unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -41,6 +41,9 @@ func NewStarterJob(k6 *v1alpha1.TestRun, hostname []string) *batchv1.Job {
 		automountServiceAccountToken, _ = strconv.ParseBool(k6.GetSpec().Starter.AutomountServiceAccountToken)
 	}
 
+	profile := k6.GetSpec().SecurityProfile
+	securityContext := newContainerSecurityContext(profile, k6.GetSpec().Starter.ContainerSecurityContext)
+
 	command, istioEnabled := newIstioCommand(k6.GetSpec().Scuttle.Enabled, []string{"sh", "-c"})
 	env := newIstioEnvVar(k6.GetSpec().Scuttle, istioEnabled)
 	return &batchv1.Job{
@@ -64,10 +67,10 @@ func NewStarterJob(k6 *v1alpha1.TestRun, hostname []string) *batchv1.Job {
 					Tolerations:                  k6.GetSpec().Starter.Tolerations,
 					TopologySpreadConstraints:    k6.GetSpec().Starter.TopologySpreadConstraints,
 					RestartPolicy:                corev1.RestartPolicyNever,
-					SecurityContext:              &k6.GetSpec().Starter.SecurityContext,
+					SecurityContext:              newPodSecurityContext(profile, k6.GetSpec().Starter.SecurityContext),
 					ImagePullSecrets:             k6.GetSpec().Starter.ImagePullSecrets,
 					Containers: []corev1.Container{
-						containers.NewStartContainer(hostname, starterImage, k6.GetSpec().Starter.ImagePullPolicy, command, env, k6.GetSpec().Starter.ContainerSecurityContext),
+						containers.NewStartContainer(hostname, starterImage, k6.GetSpec().Starter.ImagePullPolicy, command, env, *securityContext),
 					},
 				},
 			},
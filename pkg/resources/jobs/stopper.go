@@ -23,9 +23,10 @@ func NewStopJob(k6 *v1alpha1.TestRun, hostname []string) *batchv1.Job {
 
 	command, istioEnabled := newIstioCommand(k6.GetSpec().Scuttle.Enabled, []string{"sh", "-c"})
 	env := newIstioEnvVar(k6.GetSpec().Scuttle, istioEnabled)
+	securityContext := newContainerSecurityContext(k6.GetSpec().SecurityProfile, k6.GetSpec().Starter.ContainerSecurityContext)
 
 	job.Spec.Template.Spec.Containers = []corev1.Container{
-		containers.NewStopContainer(hostname, image, k6.GetSpec().Starter.ImagePullPolicy, command, env, k6.GetSpec().Starter.ContainerSecurityContext),
+		containers.NewStopContainer(hostname, image, k6.GetSpec().Starter.ImagePullPolicy, command, env, *securityContext),
 	}
 
 	return job
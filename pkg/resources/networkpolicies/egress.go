@@ -0,0 +1,301 @@
+package networkpolicies
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// defaultCloudHost receives metrics of the cloud output.
+const defaultCloudHost = "https://ingest.k6.io"
+
+// outputEnvVars are environment variables of k6 outputs holding their address.
+var outputEnvVars = []string{
+	"K6_CLOUD_HOST",
+	"K6_PROMETHEUS_RW_SERVER_URL",
+	"K6_INFLUXDB_ADDR",
+	"K6_OTEL_GRPC_EXPORTER_ENDPOINT",
+	"K6_OTEL_HTTP_EXPORTER_ENDPOINT",
+}
+
+// Enabled tells whether egress of the runners of the TestRun is restricted:
+// either the TestRun declares targets or a policy of its namespace does.
+// PLZ test runs are never restricted: their egress, e.g. the archive of the
+// test and the cloud logs, is decided by k6 Cloud and they can't list targets.
+func Enabled(k6 *v1alpha1.TestRun, policies []v1alpha1.TestRunPolicy) bool {
+	if v1alpha1.IsTrue(k6, v1alpha1.CloudPLZTestRun) {
+		return false
+	}
+	return len(k6.GetSpec().Targets) > 0 || required(policies)
+}
+
+func required(policies []v1alpha1.TestRunPolicy) bool {
+	for i := range policies {
+		if len(policies[i].Spec.Targets) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the name of the egress NetworkPolicy of the runners.
+func Name(k6 *v1alpha1.TestRun) string {
+	return fmt.Sprintf("%s-egress", k6.NamespacedName().Name)
+}
+
+// Targets returns spec.targets together with the outputs of the runners
+// that have their address in the environment of the runners.
+func Targets(k6 *v1alpha1.TestRun) []v1alpha1.Target {
+	targets := append([]v1alpha1.Target{}, k6.GetSpec().Targets...)
+
+	env := make(map[string]string)
+	for _, e := range k6.GetSpec().Runner.Env {
+		env[e.Name] = e.Value
+	}
+	if _, ok := env["K6_CLOUD_HOST"]; !ok && types.ParseCLI(k6.GetSpec().Arguments).HasCloudOut {
+		env["K6_CLOUD_HOST"] = defaultCloudHost
+	}
+
+	for _, name := range outputEnvVars {
+		if target, ok := addressTarget(env[name]); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// addressTarget returns the target of a URL or of a `host:port` address.
+func addressTarget(address string) (v1alpha1.Target, bool) {
+	if len(address) == 0 {
+		return v1alpha1.Target{}, false
+	}
+	if !strings.Contains(address, "://") {
+		address = "//" + address
+	}
+	u, err := url.Parse(address)
+	if err != nil || len(u.Hostname()) == 0 {
+		return v1alpha1.Target{}, false
+	}
+
+	var target v1alpha1.Target
+	if ip, err := netip.ParseAddr(u.Hostname()); err == nil {
+		target.CIDR = netip.PrefixFrom(ip, ip.BitLen()).String()
+	} else {
+		target.Host = u.Hostname()
+	}
+
+	port := u.Port()
+	if len(port) == 0 {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "http":
+			port = "80"
+		}
+	}
+	if p, err := strconv.ParseInt(port, 10, 32); err == nil {
+		target.Ports = []int32{int32(p)}
+	}
+
+	return target, true
+}
+
+// Validate checks that each target is either a host or a valid CIDR and,
+// if policies of the namespace list targets, that each of them is allowed
+// by one of the policies.
+func Validate(targets []v1alpha1.Target, policies []v1alpha1.TestRunPolicy) error {
+	var disallowed []string
+
+	for _, target := range targets {
+		if (len(target.Host) > 0) == (len(target.CIDR) > 0) {
+			return fmt.Errorf("target must have either a host or a CIDR: %+v", target)
+		}
+		if len(target.CIDR) > 0 {
+			if _, err := netip.ParsePrefix(target.CIDR); err != nil {
+				return fmt.Errorf("invalid CIDR of target: %w", err)
+			}
+		}
+
+		if !required(policies) {
+			continue
+		}
+		allowed := false
+		for i := range policies {
+			if policies[i].AllowsTarget(target) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			disallowed = append(disallowed, format(target))
+		}
+	}
+
+	if len(disallowed) > 0 {
+		return fmt.Errorf("no TestRunPolicy allows targets %s", strings.Join(disallowed, ", "))
+	}
+	return nil
+}
+
+func format(target v1alpha1.Target) string {
+	s := target.Host + target.CIDR
+	if len(target.Ports) > 0 {
+		ports := make([]string, len(target.Ports))
+		for i, port := range target.Ports {
+			ports[i] = strconv.Itoa(int(port))
+		}
+		s += ":" + strings.Join(ports, ",")
+	}
+	return s
+}
+
+// serviceHost returns the name and the namespace of the Service of the
+// cluster designated by the host: `<name>.<namespace>.svc`, optionally
+// followed by the cluster domain.
+func serviceHost(host string) (name, namespace string, ok bool) {
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	if len(labels) < 3 || labels[2] != "svc" {
+		return "", "", false
+	}
+	return labels[0], labels[1], true
+}
+
+// serviceRule returns the rule that allows egress to the pods of the Service,
+// on the ports of the pods behind the ports of the target. Services without
+// a selector have no pods to select.
+func serviceRule(target v1alpha1.Target, service *corev1.Service) (networkingv1.NetworkPolicyEgressRule, bool) {
+	if len(service.Spec.Selector) == 0 {
+		return networkingv1.NetworkPolicyEgressRule{}, false
+	}
+
+	rule := networkingv1.NetworkPolicyEgressRule{
+		To: []networkingv1.NetworkPolicyPeer{{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{corev1.LabelMetadataName: service.Namespace},
+			},
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: service.Spec.Selector,
+			},
+		}},
+	}
+
+	for _, port := range target.Ports {
+		protocol, targetPort := corev1.ProtocolTCP, intstr.FromInt32(port)
+		for _, sp := range service.Spec.Ports {
+			if sp.Port != port {
+				continue
+			}
+			if len(sp.Protocol) > 0 {
+				protocol = sp.Protocol
+			}
+			// the target port defaults to the port of the Service
+			if sp.TargetPort.Type == intstr.String || sp.TargetPort.IntVal != 0 {
+				targetPort = sp.TargetPort
+			}
+			break
+		}
+		rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &targetPort})
+	}
+
+	return rule, true
+}
+
+// NewEgressPolicy builds the NetworkPolicy of the runners that allows egress
+// only to DNS and to the targets. Hosts of Services of the cluster are
+// allowed by selecting the pods of the Service, as got with service, which
+// returns nil if the Service doesn't exist. Other hosts are resolved to their
+// current addresses with resolve.
+func NewEgressPolicy(k6 *v1alpha1.TestRun, targets []v1alpha1.Target,
+	resolve func(host string) ([]net.IP, error),
+	service func(namespace, name string) (*corev1.Service, error),
+) (*networkingv1.NetworkPolicy, error) {
+	var (
+		udp, tcp = corev1.ProtocolUDP, corev1.ProtocolTCP
+		dnsPort  = intstr.FromInt32(53)
+	)
+
+	rules := []networkingv1.NetworkPolicyEgressRule{{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &udp, Port: &dnsPort},
+			{Protocol: &tcp, Port: &dnsPort},
+		},
+	}}
+
+	for _, target := range targets {
+		if name, namespace, ok := serviceHost(target.Host); ok {
+			svc, err := service(namespace, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get Service of target %s: %w", target.Host, err)
+			}
+			if svc != nil {
+				if rule, ok := serviceRule(target, svc); ok {
+					rules = append(rules, rule)
+					continue
+				}
+			}
+		}
+
+		var cidrs []string
+		if len(target.CIDR) > 0 {
+			cidrs = append(cidrs, target.CIDR)
+		} else {
+			ips, err := resolve(target.Host)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve target %s: %w", target.Host, err)
+			}
+			for _, ip := range ips {
+				if addr, ok := netip.AddrFromSlice(ip); ok {
+					addr = addr.Unmap()
+					cidrs = append(cidrs, netip.PrefixFrom(addr, addr.BitLen()).String())
+				}
+			}
+		}
+
+		rule := networkingv1.NetworkPolicyEgressRule{}
+		for _, cidr := range cidrs {
+			rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+			})
+		}
+		for _, port := range target.Ports {
+			p := intstr.FromInt32(port)
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Protocol: &tcp, Port: &p})
+		}
+		if len(rule.To) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(k6),
+			Namespace: k6.NamespacedName().Namespace,
+			Labels: map[string]string{
+				"app":   "k6",
+				"k6_cr": k6.NamespacedName().Name,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":    "k6",
+					"k6_cr":  k6.NamespacedName().Name,
+					"runner": "true",
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      rules,
+		},
+	}, nil
+}
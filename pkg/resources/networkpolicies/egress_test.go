@@ -0,0 +1,223 @@
+package networkpolicies
+
+import (
+	"net"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newTestRun(targets ...v1alpha1.Target) *v1alpha1.TestRun {
+	return &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Targets: targets,
+		},
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	policies := []v1alpha1.TestRunPolicy{{
+		Spec: v1alpha1.TestRunPolicySpec{Targets: []v1alpha1.Target{{CIDR: "10.20.0.0/16"}}},
+	}}
+
+	if Enabled(newTestRun(), nil) {
+		t.Errorf("egress should not be restricted without targets")
+	}
+	if !Enabled(newTestRun(v1alpha1.Target{CIDR: "10.20.0.0/16"}), nil) {
+		t.Errorf("egress should be restricted with targets of the TestRun")
+	}
+	if !Enabled(newTestRun(), policies) {
+		t.Errorf("egress should be restricted with targets of a policy")
+	}
+
+	plz := newTestRun()
+	plz.Spec.TestRunID = "6543"
+	v1alpha1.Initialize(plz)
+	if Enabled(plz, policies) {
+		t.Errorf("egress of a PLZ test run should not be restricted")
+	}
+}
+
+func TestTargets(t *testing.T) {
+	k6 := newTestRun(v1alpha1.Target{Host: "shop.staging.example.com", Ports: []int32{443}})
+	k6.Spec.Arguments = "--out cloud --tag env=staging"
+	k6.Spec.Runner.Env = []corev1.EnvVar{
+		{Name: "K6_PROMETHEUS_RW_SERVER_URL", Value: "http://10.1.2.3:9090/api/v1/write"},
+		{Name: "K6_OTEL_GRPC_EXPORTER_ENDPOINT", Value: "otel-collector.monitoring:4317"},
+		{Name: "K6_INFLUXDB_ADDR", ValueFrom: &corev1.EnvVarSource{}},
+	}
+
+	expected := []v1alpha1.Target{
+		{Host: "shop.staging.example.com", Ports: []int32{443}},
+		{Host: "ingest.k6.io", Ports: []int32{443}},
+		{CIDR: "10.1.2.3/32", Ports: []int32{9090}},
+		{Host: "otel-collector.monitoring", Ports: []int32{4317}},
+	}
+
+	if diff := deep.Equal(Targets(k6), expected); diff != nil {
+		t.Errorf("Targets returned unexpected targets, diff: %s", diff)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	policies := []v1alpha1.TestRunPolicy{
+		{Spec: v1alpha1.TestRunPolicySpec{Targets: []v1alpha1.Target{
+			{Host: "*.staging.example.com", Ports: []int32{80, 443}},
+			{CIDR: "10.0.0.0/8"},
+		}}},
+		{Spec: v1alpha1.TestRunPolicySpec{Targets: []v1alpha1.Target{
+			{Host: "ingest.k6.io"},
+		}}},
+	}
+
+	testCases := []struct {
+		name    string
+		targets []v1alpha1.Target
+		valid   bool
+	}{
+		{"subdomain", []v1alpha1.Target{{Host: "shop.staging.example.com", Ports: []int32{443}}}, true},
+		{"subdomain on other port", []v1alpha1.Target{{Host: "shop.staging.example.com", Ports: []int32{8443}}}, false},
+		{"subdomain on all ports", []v1alpha1.Target{{Host: "shop.staging.example.com"}}, false},
+		{"domain of wildcard", []v1alpha1.Target{{Host: "staging.example.com", Ports: []int32{443}}}, false},
+		{"production", []v1alpha1.Target{{Host: "shop.example.com", Ports: []int32{443}}}, false},
+		{"contained CIDR", []v1alpha1.Target{{CIDR: "10.20.0.0/16"}, {Host: "ingest.k6.io", Ports: []int32{443}}}, true},
+		{"larger CIDR", []v1alpha1.Target{{CIDR: "10.0.0.0/7"}}, false},
+		{"no host or CIDR", []v1alpha1.Target{{Ports: []int32{443}}}, false},
+		{"host and CIDR", []v1alpha1.Target{{Host: "ingest.k6.io", CIDR: "10.0.0.0/8"}}, false},
+		{"invalid CIDR", []v1alpha1.Target{{CIDR: "10.0.0.0"}}, false},
+	}
+
+	for _, tc := range testCases {
+		if err := Validate(tc.targets, policies); (err == nil) != tc.valid {
+			t.Errorf("%s: expected valid to be %v, got error: %v", tc.name, tc.valid, err)
+		}
+	}
+
+	// without policies, any well-formed target is valid
+	if err := Validate([]v1alpha1.Target{{Host: "shop.example.com"}}, nil); err != nil {
+		t.Errorf("Validate without policies errored, got: %v", err)
+	}
+}
+
+func TestNewEgressPolicy(t *testing.T) {
+	var (
+		udp, tcp = corev1.ProtocolUDP, corev1.ProtocolTCP
+		dns      = intstr.FromInt32(53)
+		https    = intstr.FromInt32(443)
+	)
+
+	k6 := newTestRun()
+	targets := []v1alpha1.Target{
+		{Host: "shop.staging.example.com", Ports: []int32{443}},
+		{CIDR: "10.20.0.0/16"},
+	}
+	resolve := func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("192.0.2.10"), net.ParseIP("2001:db8::10")}, nil
+	}
+
+	expected := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-egress",
+			Namespace: "test",
+			Labels: map[string]string{
+				"app":   "k6",
+				"k6_cr": "test",
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":    "k6",
+					"k6_cr":  "test",
+					"runner": "true",
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &udp, Port: &dns},
+					{Protocol: &tcp, Port: &dns},
+				},
+			}, {
+				To: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "192.0.2.10/32"}},
+					{IPBlock: &networkingv1.IPBlock{CIDR: "2001:db8::10/128"}},
+				},
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &https}},
+			}, {
+				To: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "10.20.0.0/16"}},
+				},
+			}},
+		},
+	}
+
+	noService := func(namespace, name string) (*corev1.Service, error) {
+		return nil, nil
+	}
+
+	policy, err := NewEgressPolicy(k6, targets, resolve, noService)
+	if err != nil {
+		t.Errorf("NewEgressPolicy errored, got: %v", err)
+	}
+	if diff := deep.Equal(policy, expected); diff != nil {
+		t.Errorf("NewEgressPolicy returned unexpected data, diff: %s", diff)
+	}
+}
+
+func TestNewEgressPolicyService(t *testing.T) {
+	var (
+		tcp     = corev1.ProtocolTCP
+		webPort = intstr.FromString("web")
+	)
+
+	k6 := newTestRun()
+	k6.Spec.Runner.Env = []corev1.EnvVar{
+		{Name: "K6_PROMETHEUS_RW_SERVER_URL", Value: "http://prometheus.monitoring.svc.cluster.local:9090/api/v1/write"},
+	}
+	resolve := func(host string) ([]net.IP, error) {
+		t.Errorf("host of a Service with a selector should not be resolved: %s", host)
+		return nil, nil
+	}
+	service := func(namespace, name string) (*corev1.Service, error) {
+		if namespace != "monitoring" || name != "prometheus" {
+			t.Errorf("unexpected Service %s/%s", namespace, name)
+		}
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app.kubernetes.io/name": "prometheus"},
+				Ports:    []corev1.ServicePort{{Port: 9090, TargetPort: webPort}},
+			},
+		}, nil
+	}
+
+	policy, err := NewEgressPolicy(k6, Targets(k6), resolve, service)
+	if err != nil {
+		t.Fatalf("NewEgressPolicy errored, got: %v", err)
+	}
+
+	expected := []networkingv1.NetworkPolicyEgressRule{{
+		To: []networkingv1.NetworkPolicyPeer{{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"},
+			},
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app.kubernetes.io/name": "prometheus"},
+			},
+		}},
+		Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &webPort}},
+	}}
+	if diff := deep.Equal(policy.Spec.Egress[1:], expected); diff != nil {
+		t.Errorf("NewEgressPolicy returned unexpected rules, diff: %s", diff)
+	}
+}
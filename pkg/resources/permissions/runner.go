@@ -0,0 +1,107 @@
+package permissions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Enabled tells whether the runners of the TestRun request permissions.
+func Enabled(k6 *v1alpha1.TestRun) bool {
+	return len(k6.GetSpec().Runner.Permissions) > 0
+}
+
+// Name returns the name of the ServiceAccount, Role and RoleBinding of the runners.
+func Name(k6 *v1alpha1.TestRun) string {
+	return fmt.Sprintf("%s-runner", k6.NamespacedName().Name)
+}
+
+func newObjectMeta(k6 *v1alpha1.TestRun) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      Name(k6),
+		Namespace: k6.NamespacedName().Namespace,
+		Labels: map[string]string{
+			"app":   "k6",
+			"k6_cr": k6.NamespacedName().Name,
+		},
+	}
+}
+
+// NewServiceAccount builds the ServiceAccount of the runners.
+func NewServiceAccount(k6 *v1alpha1.TestRun) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: newObjectMeta(k6),
+	}
+}
+
+// NewRole builds the Role with the permissions requested for the runners.
+func NewRole(k6 *v1alpha1.TestRun) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: newObjectMeta(k6),
+		Rules:      k6.GetSpec().Runner.Permissions,
+	}
+}
+
+// NewRoleBinding builds the RoleBinding of the Role to the ServiceAccount of the runners.
+func NewRoleBinding(k6 *v1alpha1.TestRun) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: newObjectMeta(k6),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     Name(k6),
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      Name(k6),
+			Namespace: k6.NamespacedName().Namespace,
+		}},
+	}
+}
+
+// Disallowed returns the requested rules which are not allowed by any of the policies.
+func Disallowed(k6 *v1alpha1.TestRun, policies []v1alpha1.TestRunPolicy) []rbacv1.PolicyRule {
+	var disallowed []rbacv1.PolicyRule
+
+	for _, rule := range k6.GetSpec().Runner.Permissions {
+		allowed := false
+		for i := range policies {
+			if policies[i].AllowsRule(rule) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			disallowed = append(disallowed, rule)
+		}
+	}
+
+	return disallowed
+}
+
+// Describe returns a human-readable form of the rule, e.g. `get,list on pods, deployments.apps`.
+func Describe(rule rbacv1.PolicyRule) string {
+	if len(rule.NonResourceURLs) > 0 {
+		return fmt.Sprintf("%s on %s", strings.Join(rule.Verbs, ","), strings.Join(rule.NonResourceURLs, ", "))
+	}
+
+	var resources []string
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			if len(group) > 0 {
+				resource += "." + group
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	description := fmt.Sprintf("%s on %s", strings.Join(rule.Verbs, ","), strings.Join(resources, ", "))
+	if len(rule.ResourceNames) > 0 {
+		description += fmt.Sprintf(" named %s", strings.Join(rule.ResourceNames, ","))
+	}
+	return description
+}
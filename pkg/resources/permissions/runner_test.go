@@ -0,0 +1,137 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestRun(rules ...rbacv1.PolicyRule) *v1alpha1.TestRun {
+	return &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Runner: v1alpha1.Pod{
+				Permissions: rules,
+			},
+		},
+	}
+}
+
+func TestDisallowed(t *testing.T) {
+	policies := []v1alpha1.TestRunPolicy{
+		{Spec: v1alpha1.TestRunPolicySpec{Permissions: []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"pods", "services"},
+			Verbs:     []string{"get", "list", "watch"},
+		}}}},
+		{Spec: v1alpha1.TestRunPolicySpec{Permissions: []rbacv1.PolicyRule{{
+			APIGroups: []string{"apps"},
+			Resources: []string{"deployments"},
+			Verbs:     []string{"*"},
+		}, {
+			APIGroups:     []string{""},
+			Resources:     []string{"pods/ephemeralcontainers"},
+			ResourceNames: []string{"app-1", "app-2"},
+			Verbs:         []string{"update"},
+		}}}},
+	}
+
+	tests := []struct {
+		name    string
+		rule    rbacv1.PolicyRule
+		allowed bool
+	}{
+		{
+			name:    "subset of one rule",
+			rule:    rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list", "watch"}},
+			allowed: true,
+		},
+		{
+			name:    "covered by a wildcard",
+			rule:    rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "patch"}},
+			allowed: true,
+		},
+		{
+			name:    "resource names within allowed names",
+			rule:    rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods/ephemeralcontainers"}, ResourceNames: []string{"app-2"}, Verbs: []string{"update"}},
+			allowed: true,
+		},
+		{
+			name:    "all names of a resource limited to names",
+			rule:    rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods/ephemeralcontainers"}, Verbs: []string{"update"}},
+			allowed: false,
+		},
+		{
+			name:    "verb not allowed",
+			rule:    rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"delete"}},
+			allowed: false,
+		},
+		{
+			name:    "wildcard not allowed",
+			rule:    rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"*"}, Verbs: []string{"get"}},
+			allowed: false,
+		},
+		{
+			name:    "non-resource URLs",
+			rule:    rbacv1.PolicyRule{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+			allowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			disallowed := Disallowed(newTestRun(tt.rule), policies)
+			if allowed := len(disallowed) == 0; allowed != tt.allowed {
+				t.Errorf("expected allowed %v, got disallowed rules %v", tt.allowed, disallowed)
+			}
+		})
+	}
+
+	if disallowed := Disallowed(newTestRun(tests[0].rule), nil); len(disallowed) != 1 {
+		t.Errorf("expected rules to be disallowed without policies")
+	}
+}
+
+func TestNewRoleBinding(t *testing.T) {
+	binding := NewRoleBinding(newTestRun())
+
+	expected := []rbacv1.Subject{{Kind: "ServiceAccount", Name: "test-runner", Namespace: "test"}}
+	if diff := deep.Equal(binding.Subjects, expected); diff != nil {
+		t.Errorf("NewRoleBinding returned unexpected subjects, diff: %s", diff)
+	}
+	if binding.RoleRef.Kind != "Role" || binding.RoleRef.Name != "test-runner" {
+		t.Errorf("NewRoleBinding returned unexpected role %v", binding.RoleRef)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		rule     rbacv1.PolicyRule
+		expected string
+	}{
+		{
+			rbacv1.PolicyRule{APIGroups: []string{"", "apps"}, Resources: []string{"pods", "deployments"}, Verbs: []string{"get", "list"}},
+			"get,list on pods, deployments, pods.apps, deployments.apps",
+		},
+		{
+			rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, ResourceNames: []string{"app-1"}, Verbs: []string{"delete"}},
+			"delete on pods named app-1",
+		},
+		{
+			rbacv1.PolicyRule{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+			"get on /metrics",
+		},
+	}
+
+	for _, test := range tests {
+		if description := Describe(test.rule); description != test.expected {
+			t.Errorf("Describe returned %q, expected %q", description, test.expected)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package podgroups
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// CoschedulingLabel is the pod label with the PodGroup of scheduler-plugins coscheduling.
+	CoschedulingLabel = "scheduling.x-k8s.io/pod-group"
+	// VolcanoAnnotation is the pod annotation with the PodGroup of Volcano.
+	VolcanoAnnotation = "scheduling.k8s.io/group-name"
+)
+
+var (
+	coschedulingGVK = schema.GroupVersionKind{Group: "scheduling.x-k8s.io", Version: "v1alpha1", Kind: "PodGroup"}
+	volcanoGVK      = schema.GroupVersionKind{Group: "scheduling.volcano.sh", Version: "v1beta1", Kind: "PodGroup"}
+
+	defaultSchedulerNames = map[v1alpha1.GangScheduler]string{
+		v1alpha1.GangCoscheduling: "scheduler-plugins-scheduler",
+		v1alpha1.GangVolcano:      "volcano",
+	}
+
+	// phases in which all members of the group have been scheduled
+	scheduledPhases = map[v1alpha1.GangScheduler][]string{
+		v1alpha1.GangCoscheduling: {"Scheduled", "Running", "Succeeded", "Finished"},
+		v1alpha1.GangVolcano:      {"Running", "Completed"},
+	}
+
+	// phases in which the group won't be scheduled anymore; Volcano keeps
+	// retrying, so only the timeout applies to it
+	failedPhases = map[v1alpha1.GangScheduler][]string{
+		v1alpha1.GangCoscheduling: {"Failed"},
+	}
+)
+
+// Scheduler returns the gang scheduler of the TestRun, or an empty string
+// if gang scheduling is not configured.
+func Scheduler(k6 *v1alpha1.TestRun) v1alpha1.GangScheduler {
+	gang := k6.GetSpec().GangScheduling
+	if gang == nil {
+		return ""
+	}
+	if len(gang.Scheduler) == 0 {
+		return v1alpha1.GangCoscheduling
+	}
+	return gang.Scheduler
+}
+
+// SchedulerName returns the scheduler name of the runner pods.
+func SchedulerName(k6 *v1alpha1.TestRun) string {
+	if name := k6.GetSpec().GangScheduling.SchedulerName; len(name) > 0 {
+		return name
+	}
+	return defaultSchedulerNames[Scheduler(k6)]
+}
+
+// Name returns the name of the PodGroup of the runners.
+func Name(k6 *v1alpha1.TestRun) string {
+	return fmt.Sprintf("%s-runners", k6.NamespacedName().Name)
+}
+
+// NewPodGroup builds the PodGroup of the runners of the TestRun, requiring
+// all of them to be scheduled at once. The PodGroup API is not vendored,
+// so it is built as unstructured.
+func NewPodGroup(k6 *v1alpha1.TestRun) *unstructured.Unstructured {
+	scheduler := Scheduler(k6)
+	gang := k6.GetSpec().GangScheduling
+
+	spec := map[string]interface{}{
+		"minMember": int64(k6.GetSpec().Parallelism),
+	}
+	if scheduler == v1alpha1.GangCoscheduling && gang.ScheduleTimeoutSeconds != nil {
+		spec["scheduleTimeoutSeconds"] = int64(*gang.ScheduleTimeoutSeconds)
+	}
+	if scheduler == v1alpha1.GangVolcano && len(gang.Queue) > 0 {
+		spec["queue"] = gang.Queue
+	}
+
+	pg := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	pg.SetGroupVersionKind(GroupVersionKind(k6))
+	pg.SetName(Name(k6))
+	pg.SetNamespace(k6.NamespacedName().Namespace)
+	pg.SetLabels(map[string]string{
+		"app":   "k6",
+		"k6_cr": k6.NamespacedName().Name,
+	})
+
+	return pg
+}
+
+// GroupVersionKind returns the kind of PodGroup of the gang scheduler of the TestRun.
+func GroupVersionKind(k6 *v1alpha1.TestRun) schema.GroupVersionKind {
+	if Scheduler(k6) == v1alpha1.GangVolcano {
+		return volcanoGVK
+	}
+	return coschedulingGVK
+}
+
+// Phase returns the phase of the PodGroup reported by the gang scheduler and
+// whether all members of the group have been scheduled in it.
+func Phase(k6 *v1alpha1.TestRun, pg *unstructured.Unstructured) (phase string, scheduled bool) {
+	phase, _, _ = unstructured.NestedString(pg.Object, "status", "phase")
+	for _, p := range scheduledPhases[Scheduler(k6)] {
+		if phase == p {
+			return phase, true
+		}
+	}
+	return phase, false
+}
+
+// Failed returns why the PodGroup won't be scheduled, or an empty string.
+// The group fails if the gang scheduler reports a failed phase, or if it is
+// still not scheduled after spec.gangScheduling.scheduleTimeoutSeconds.
+func Failed(k6 *v1alpha1.TestRun, pg *unstructured.Unstructured, now time.Time) string {
+	phase, scheduled := Phase(k6, pg)
+	if scheduled {
+		return ""
+	}
+	for _, p := range failedPhases[Scheduler(k6)] {
+		if phase == p {
+			return fmt.Sprintf("PodGroup %s is in phase %s", pg.GetName(), phase)
+		}
+	}
+
+	timeout := k6.GetSpec().GangScheduling.ScheduleTimeoutSeconds
+	created := pg.GetCreationTimestamp()
+	if timeout == nil || created.IsZero() {
+		return ""
+	}
+	if waited := now.Sub(created.Time); waited > time.Duration(*timeout)*time.Second {
+		return fmt.Sprintf("PodGroup %s is not scheduled after %ds, in phase %s", pg.GetName(), *timeout, phase)
+	}
+	return ""
+}
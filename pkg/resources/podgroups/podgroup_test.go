@@ -0,0 +1,138 @@
+package podgroups
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestRun(gang *v1alpha1.GangScheduling) *v1alpha1.TestRun {
+	return &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunSpec{
+			Parallelism:    40,
+			GangScheduling: gang,
+		},
+	}
+}
+
+func TestNewPodGroup(t *testing.T) {
+	timeout := int32(60)
+
+	tests := []struct {
+		name          string
+		gang          *v1alpha1.GangScheduling
+		apiVersion    string
+		schedulerName string
+		spec          map[string]interface{}
+	}{
+		{
+			name:          "coscheduling by default",
+			gang:          &v1alpha1.GangScheduling{ScheduleTimeoutSeconds: &timeout, Queue: "ignored"},
+			apiVersion:    "scheduling.x-k8s.io/v1alpha1",
+			schedulerName: "scheduler-plugins-scheduler",
+			spec:          map[string]interface{}{"minMember": int64(40), "scheduleTimeoutSeconds": int64(60)},
+		},
+		{
+			name:          "volcano",
+			gang:          &v1alpha1.GangScheduling{Scheduler: v1alpha1.GangVolcano, ScheduleTimeoutSeconds: &timeout, Queue: "load-tests"},
+			apiVersion:    "scheduling.volcano.sh/v1beta1",
+			schedulerName: "volcano",
+			spec:          map[string]interface{}{"minMember": int64(40), "queue": "load-tests"},
+		},
+		{
+			name:          "custom scheduler name",
+			gang:          &v1alpha1.GangScheduling{SchedulerName: "gang-scheduler"},
+			apiVersion:    "scheduling.x-k8s.io/v1alpha1",
+			schedulerName: "gang-scheduler",
+			spec:          map[string]interface{}{"minMember": int64(40)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k6 := newTestRun(tt.gang)
+			pg := NewPodGroup(k6)
+
+			if pg.GetAPIVersion() != tt.apiVersion || pg.GetKind() != "PodGroup" {
+				t.Errorf("unexpected kind %s %s", pg.GetAPIVersion(), pg.GetKind())
+			}
+			if pg.GetName() != "test-runners" || pg.GetNamespace() != "test" {
+				t.Errorf("unexpected PodGroup %s/%s", pg.GetNamespace(), pg.GetName())
+			}
+			if diff := deep.Equal(pg.Object["spec"], tt.spec); diff != nil {
+				t.Errorf("NewPodGroup returned unexpected spec, diff: %s", diff)
+			}
+			if name := SchedulerName(k6); name != tt.schedulerName {
+				t.Errorf("expected scheduler name %s, got %s", tt.schedulerName, name)
+			}
+		})
+	}
+}
+
+func TestPhase(t *testing.T) {
+	tests := []struct {
+		scheduler v1alpha1.GangScheduler
+		phase     string
+		scheduled bool
+	}{
+		{v1alpha1.GangCoscheduling, "", false},
+		{v1alpha1.GangCoscheduling, "Pending", false},
+		{v1alpha1.GangCoscheduling, "Scheduling", false},
+		{v1alpha1.GangCoscheduling, "Scheduled", true},
+		{v1alpha1.GangCoscheduling, "Running", true},
+		{v1alpha1.GangVolcano, "Inqueue", false},
+		{v1alpha1.GangVolcano, "Running", true},
+	}
+
+	for _, tt := range tests {
+		k6 := newTestRun(&v1alpha1.GangScheduling{Scheduler: tt.scheduler})
+		pg := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"phase": tt.phase},
+		}}
+
+		if phase, scheduled := Phase(k6, pg); phase != tt.phase || scheduled != tt.scheduled {
+			t.Errorf("%s %q: expected scheduled %v, got %q %v", tt.scheduler, tt.phase, tt.scheduled, phase, scheduled)
+		}
+	}
+}
+
+func TestFailed(t *testing.T) {
+	timeout := int32(60)
+	now := time.Now()
+	tests := []struct {
+		name    string
+		gang    *v1alpha1.GangScheduling
+		phase   string
+		created time.Time
+		failed  bool
+	}{
+		{"pending", &v1alpha1.GangScheduling{ScheduleTimeoutSeconds: &timeout}, "Pending", now.Add(-time.Second), false},
+		{"failed", &v1alpha1.GangScheduling{}, "Failed", now.Add(-time.Second), true},
+		{"timed out", &v1alpha1.GangScheduling{ScheduleTimeoutSeconds: &timeout}, "Pending", now.Add(-2 * time.Minute), true},
+		{"volcano timed out", &v1alpha1.GangScheduling{Scheduler: v1alpha1.GangVolcano, ScheduleTimeoutSeconds: &timeout}, "Inqueue", now.Add(-2 * time.Minute), true},
+		{"no timeout", &v1alpha1.GangScheduling{}, "Pending", now.Add(-time.Hour), false},
+		{"scheduled", &v1alpha1.GangScheduling{ScheduleTimeoutSeconds: &timeout}, "Running", now.Add(-2 * time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k6 := newTestRun(tt.gang)
+			pg := &unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{"phase": tt.phase},
+			}}
+			pg.SetName("test-runners")
+			pg.SetCreationTimestamp(metav1.NewTime(tt.created))
+
+			if reason := Failed(k6, pg, now); (len(reason) > 0) != tt.failed {
+				t.Errorf("expected failed %v, got %q", tt.failed, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package testrun
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// AlertOutcome is what must be done with the runners after an alert.
+type AlertOutcome string
+
+const (
+	AlertNoop   AlertOutcome = ""
+	AlertPause  AlertOutcome = "Pause"
+	AlertResume AlertOutcome = "Resume"
+	AlertStop   AlertOutcome = "Stop"
+)
+
+// MatchAlertAction returns the first action of spec.alertActions whose
+// selector matches labels of the alert, or nil.
+func MatchAlertAction(k6 *v1alpha1.TestRun, alert types.Alert) (*v1alpha1.AlertAction, error) {
+	for i := range k6.Spec.AlertActions {
+		action := &k6.Spec.AlertActions[i]
+		selector, err := metav1.LabelSelectorAsSelector(&action.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector of alert action %d: %w", i, err)
+		}
+		// an empty selector would match every alert
+		if selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(alert.Labels)) {
+			return action, nil
+		}
+	}
+	return nil, nil
+}
+
+// RecordAlert records the alert in the status of the TestRun and returns
+// what must be done with the runners. Repeated notifications of the same
+// alert are recorded only once. A resolved alert resumes the test run only
+// if its action has autoResume and no other alert keeps the test run paused.
+func RecordAlert(k6 *v1alpha1.TestRun, action *v1alpha1.AlertAction, alert types.Alert) (AlertOutcome, bool) {
+	fingerprint := alert.Fingerprint
+	if len(fingerprint) == 0 {
+		fingerprint = labels.Set(alert.Labels).String()
+	}
+
+	status := k6.GetStatus()
+	var record *v1alpha1.AlertRecord
+	for i := range status.Alerts {
+		if status.Alerts[i].Fingerprint == fingerprint {
+			record = &status.Alerts[i]
+			break
+		}
+	}
+
+	switch alert.Status {
+	case types.AlertFiring:
+		if record != nil && record.ResolvedAt == nil {
+			return AlertNoop, false
+		}
+
+		firedAt := alert.StartsAt
+		if firedAt.IsZero() {
+			firedAt = time.Now()
+		}
+		fired := v1alpha1.AlertRecord{
+			Name:        alert.Labels["alertname"],
+			Fingerprint: fingerprint,
+			Labels:      alert.Labels,
+			Action:      action.Action,
+			FiredAt:     metav1.NewTime(firedAt),
+		}
+		if record != nil {
+			*record = fired
+		} else {
+			status.Alerts = append(status.Alerts, fired)
+		}
+
+		if action.Action == v1alpha1.AlertStop {
+			return AlertStop, true
+		}
+		return AlertPause, true
+
+	case types.AlertResolved:
+		if record == nil || record.ResolvedAt != nil {
+			return AlertNoop, false
+		}
+
+		resolvedAt := metav1.Now()
+		if !alert.EndsAt.IsZero() {
+			resolvedAt = metav1.NewTime(alert.EndsAt)
+		}
+		record.ResolvedAt = &resolvedAt
+
+		if record.Action != v1alpha1.AlertPause || !action.AutoResume {
+			return AlertNoop, true
+		}
+		for _, other := range status.Alerts {
+			if other.Action == v1alpha1.AlertPause && other.ResolvedAt == nil {
+				return AlertNoop, true
+			}
+		}
+		return AlertResume, true
+	}
+
+	return AlertNoop, false
+}
@@ -0,0 +1,120 @@
+package testrun
+
+import (
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func alertsTestRun() *v1alpha1.TestRun {
+	return &v1alpha1.TestRun{
+		Spec: v1alpha1.TestRunSpec{
+			AlertActions: []v1alpha1.AlertAction{
+				{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"severity": "critical"},
+					},
+					Action: v1alpha1.AlertStop,
+				},
+				{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"service": "cart"},
+					},
+					Action:     v1alpha1.AlertPause,
+					AutoResume: true,
+				},
+			},
+		},
+	}
+}
+
+func Test_MatchAlertAction(t *testing.T) {
+	k6 := alertsTestRun()
+
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected *v1alpha1.AlertAction
+	}{
+		{"stop", map[string]string{"service": "cart", "severity": "critical"}, &k6.Spec.AlertActions[0]},
+		{"pause", map[string]string{"service": "cart", "severity": "warning"}, &k6.Spec.AlertActions[1]},
+		{"no match", map[string]string{"service": "checkout"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			action, err := MatchAlertAction(k6, types.Alert{Labels: test.labels})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if action != test.expected {
+				t.Errorf("MatchAlertAction returned unexpected action: %v", action)
+			}
+		})
+	}
+
+	empty := &v1alpha1.TestRun{Spec: v1alpha1.TestRunSpec{
+		AlertActions: []v1alpha1.AlertAction{{Action: v1alpha1.AlertStop}},
+	}}
+	if action, _ := MatchAlertAction(empty, types.Alert{Labels: map[string]string{"a": "b"}}); action != nil {
+		t.Errorf("MatchAlertAction matched an empty selector")
+	}
+}
+
+func Test_RecordAlert(t *testing.T) {
+	k6 := alertsTestRun()
+	pause := &k6.Spec.AlertActions[1]
+
+	latency := types.Alert{
+		Status:      types.AlertFiring,
+		Labels:      map[string]string{"alertname": "HighLatency", "service": "cart"},
+		Fingerprint: "a1",
+	}
+	errorsAlert := types.Alert{
+		Status:      types.AlertFiring,
+		Labels:      map[string]string{"alertname": "HighErrorRate", "service": "cart"},
+		Fingerprint: "b2",
+	}
+
+	steps := []struct {
+		name    string
+		alert   types.Alert
+		outcome AlertOutcome
+		changed bool
+	}{
+		{"first alert fires", latency, AlertPause, true},
+		{"repeated notification", latency, AlertNoop, false},
+		{"second alert fires", errorsAlert, AlertPause, true},
+		{"first alert resolves", resolved(latency), AlertNoop, true},
+		{"second alert resolves", resolved(errorsAlert), AlertResume, true},
+		{"repeated resolution", resolved(errorsAlert), AlertNoop, false},
+		{"first alert fires again", latency, AlertPause, true},
+	}
+
+	for _, step := range steps {
+		outcome, changed := RecordAlert(k6, pause, step.alert)
+		if outcome != step.outcome || changed != step.changed {
+			t.Errorf("%s: RecordAlert returned %q, %v", step.name, outcome, changed)
+		}
+	}
+
+	if len(k6.Status.Alerts) != 2 {
+		t.Fatalf("RecordAlert recorded unexpected alerts: %+v", k6.Status.Alerts)
+	}
+	if k6.Status.Alerts[0].Name != "HighLatency" || k6.Status.Alerts[0].ResolvedAt != nil {
+		t.Errorf("RecordAlert recorded unexpected alert: %+v", k6.Status.Alerts[0])
+	}
+
+	stop, _ := MatchAlertAction(k6, types.Alert{Labels: map[string]string{"severity": "critical"}})
+	critical := types.Alert{Status: types.AlertFiring, Labels: map[string]string{"severity": "critical"}}
+	if outcome, _ := RecordAlert(k6, stop, critical); outcome != AlertStop {
+		t.Errorf("RecordAlert returned unexpected outcome: %q", outcome)
+	}
+}
+
+func resolved(alert types.Alert) types.Alert {
+	alert.Status = types.AlertResolved
+	return alert
+}
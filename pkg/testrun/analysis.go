@@ -0,0 +1,191 @@
+package testrun
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Phases of the analysis.
+const (
+	AnalysisRunning = "Running"
+	AnalysisPassed  = "Passed"
+	AnalysisFailed  = "Failed"
+	AnalysisError   = "Error"
+)
+
+const (
+	// RegressionMetric is compared against the baseline by default.
+	RegressionMetric = "http_req_duration.p(95)"
+	// DefaultMaxRegression is the default allowed relative increase of RegressionMetric.
+	DefaultMaxRegression = 0.1
+)
+
+// Verdict is the outcome of the analysis, as returned to Argo Rollouts and Flagger.
+type Verdict struct {
+	Phase   string `json:"phase"`
+	TestRun string `json:"testRun"`
+	// Passed is true only once the test run has finished with passing
+	// thresholds and no regression.
+	Passed           bool               `json:"passed"`
+	ThresholdsPassed bool               `json:"thresholdsPassed"`
+	FailedThresholds []string           `json:"failedThresholds,omitempty"`
+	Regression       *Regression        `json:"regression,omitempty"`
+	Metrics          map[string]float64 `json:"metrics,omitempty"`
+	Message          string             `json:"message,omitempty"`
+}
+
+// Regression compares a metric of the test run with the baseline test run.
+type Regression struct {
+	Metric        string  `json:"metric"`
+	Baseline      string  `json:"baseline"`
+	BaselineValue float64 `json:"baselineValue"`
+	Value         float64 `json:"value"`
+	// Change is relative to the baseline value, e.g. 0.05 is a 5% increase.
+	Change    float64 `json:"change"`
+	Regressed bool    `json:"regressed"`
+}
+
+// AnalysisTestName is the name of the TestRun created by the analysis
+// endpoint for the run given by the caller, e.g. a pod template hash.
+func AnalysisTestName(trigger *v1alpha1.TestRunTrigger, run string) string {
+	h := sha256.Sum256([]byte(run))
+	return fmt.Sprintf("%s-analysis-%s", trigger.Name, hex.EncodeToString(h[:])[:8])
+}
+
+// NewAnalysisTestRun creates a TestRun from the template of the trigger for
+// the analysis run. Results are required for the verdict so the report is
+// always enabled.
+func NewAnalysisTestRun(trigger *v1alpha1.TestRunTrigger, run string) *v1alpha1.TestRun {
+	template := trigger.Spec.Template.DeepCopy()
+
+	labels := map[string]string{}
+	for k, v := range template.Metadata.Labels {
+		labels[k] = v
+	}
+	labels[v1alpha1.TriggerLabel] = trigger.Name
+	labels[v1alpha1.AnalysisLabel] = "true"
+
+	annotations := map[string]string{}
+	for k, v := range template.Metadata.Annotations {
+		annotations[k] = v
+	}
+	annotations[v1alpha1.AnalysisRunAnnotation] = run
+
+	template.Spec.Report.JUnit = true
+
+	return &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        AnalysisTestName(trigger, run),
+			Namespace:   trigger.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: template.Spec,
+	}
+}
+
+// AnalysisBaseline returns the latest finished analysis TestRun with passing
+// thresholds, other than the given one. It returns nil if there is none.
+func AnalysisBaseline(k6 *v1alpha1.TestRun, candidates []v1alpha1.TestRun) *v1alpha1.TestRun {
+	var baselines []*v1alpha1.TestRun
+	for i := range candidates {
+		c := &candidates[i]
+		if c.Name == k6.Name || c.Status.Stage != "finished" ||
+			c.Status.Results == nil || !c.Status.Results.ThresholdsPassed {
+			continue
+		}
+		baselines = append(baselines, c)
+	}
+	if len(baselines) == 0 {
+		return nil
+	}
+
+	sort.Slice(baselines, func(i, j int) bool {
+		return baselines[j].CreationTimestamp.Before(&baselines[i].CreationTimestamp)
+	})
+	return baselines[0]
+}
+
+// NewVerdict evaluates the TestRun. If baseline is not nil, the regression
+// metric is compared to it and the verdict fails if it increased by more
+// than maxRegression.
+func NewVerdict(k6, baseline *v1alpha1.TestRun, metric string, maxRegression float64) *Verdict {
+	verdict := &Verdict{
+		Phase:   AnalysisRunning,
+		TestRun: k6.Name,
+	}
+
+	switch k6.Status.Stage {
+	case "error":
+		verdict.Phase = AnalysisError
+		verdict.Message = "test run has failed"
+		if c := meta.FindStatusCondition(k6.Status.Conditions, v1alpha1.InitializerFailed); c != nil && len(c.Message) > 0 {
+			verdict.Message = c.Message
+		} else if k6.Status.RunnerErrors != nil && len(k6.Status.RunnerErrors.Messages) > 0 {
+			verdict.Message = k6.Status.RunnerErrors.Messages[0]
+		}
+		return verdict
+	case "finished":
+	default:
+		return verdict
+	}
+
+	results := k6.Status.Results
+	if results == nil {
+		verdict.Phase = AnalysisError
+		verdict.Message = "test run has finished without results"
+		return verdict
+	}
+
+	verdict.ThresholdsPassed = results.ThresholdsPassed
+	verdict.FailedThresholds = results.FailedThresholds
+	verdict.Metrics = parseMetrics(results.Metrics)
+
+	if baseline != nil && baseline.Status.Results != nil {
+		baselineValue, hasBaseline := parseMetrics(baseline.Status.Results.Metrics)[metric]
+		value, hasValue := verdict.Metrics[metric]
+		if hasBaseline && hasValue && baselineValue > 0 {
+			change := (value - baselineValue) / baselineValue
+			verdict.Regression = &Regression{
+				Metric:        metric,
+				Baseline:      baseline.Name,
+				BaselineValue: baselineValue,
+				Value:         value,
+				Change:        change,
+				Regressed:     change > maxRegression,
+			}
+		}
+	}
+
+	verdict.Passed = verdict.ThresholdsPassed && (verdict.Regression == nil || !verdict.Regression.Regressed)
+	if verdict.Passed {
+		verdict.Phase = AnalysisPassed
+	} else {
+		verdict.Phase = AnalysisFailed
+		if verdict.Regression != nil && verdict.Regression.Regressed {
+			verdict.Message = fmt.Sprintf("%s regressed by %.1f%% against %s",
+				metric, verdict.Regression.Change*100, baseline.Name)
+		} else {
+			verdict.Message = "thresholds have failed"
+		}
+	}
+
+	return verdict
+}
+
+func parseMetrics(metrics map[string]string) map[string]float64 {
+	parsed := make(map[string]float64, len(metrics))
+	for k, v := range metrics {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			parsed[k] = f
+		}
+	}
+	return parsed
+}
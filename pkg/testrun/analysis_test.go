@@ -0,0 +1,159 @@
+package testrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_NewAnalysisTestRun(t *testing.T) {
+	trigger := &v1alpha1.TestRunTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cart",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunTriggerSpec{
+			Template: v1alpha1.TestRunTemplate{
+				Spec: v1alpha1.TestRunSpec{
+					Parallelism: 2,
+				},
+			},
+		},
+	}
+
+	k6 := NewAnalysisTestRun(trigger, "6d4cf56db6")
+
+	if k6.Name != AnalysisTestName(trigger, "6d4cf56db6") || k6.Name == AnalysisTestName(trigger, "7c5b8f9d44") {
+		t.Errorf("NewAnalysisTestRun returned unexpected name: %s", k6.Name)
+	}
+	if diff := deep.Equal(map[string]string{
+		v1alpha1.TriggerLabel:  "cart",
+		v1alpha1.AnalysisLabel: "true",
+	}, k6.Labels); diff != nil {
+		t.Errorf("NewAnalysisTestRun returned unexpected labels, diff: %s", diff)
+	}
+	if k6.Annotations[v1alpha1.AnalysisRunAnnotation] != "6d4cf56db6" {
+		t.Errorf("NewAnalysisTestRun returned unexpected annotations: %v", k6.Annotations)
+	}
+	if !k6.Spec.Report.JUnit {
+		t.Errorf("NewAnalysisTestRun did not enable the report")
+	}
+	if trigger.Spec.Template.Spec.Report.JUnit {
+		t.Errorf("NewAnalysisTestRun modified the template")
+	}
+}
+
+func analysisTestRun(name, stage string, created time.Time, results *v1alpha1.TestRunResults) v1alpha1.TestRun {
+	return v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: v1alpha1.TestRunStatus{
+			Stage:   v1alpha1.Stage(stage),
+			Results: results,
+		},
+	}
+}
+
+func Test_AnalysisBaseline(t *testing.T) {
+	now := time.Now()
+	passed := &v1alpha1.TestRunResults{ThresholdsPassed: true}
+	failed := &v1alpha1.TestRunResults{ThresholdsPassed: false}
+
+	current := analysisTestRun("current", "started", now, nil)
+	candidates := []v1alpha1.TestRun{
+		analysisTestRun("old", "finished", now.Add(-3*time.Hour), passed),
+		analysisTestRun("previous", "finished", now.Add(-2*time.Hour), passed),
+		analysisTestRun("failed", "finished", now.Add(-time.Hour), failed),
+		analysisTestRun("errored", "error", now.Add(-time.Hour), nil),
+		current,
+	}
+
+	if baseline := AnalysisBaseline(&current, candidates); baseline == nil || baseline.Name != "previous" {
+		t.Errorf("AnalysisBaseline returned unexpected baseline: %v", baseline)
+	}
+	if baseline := AnalysisBaseline(&current, candidates[2:]); baseline != nil {
+		t.Errorf("AnalysisBaseline returned unexpected baseline: %s", baseline.Name)
+	}
+}
+
+func Test_NewVerdict(t *testing.T) {
+	now := time.Now()
+	baseline := analysisTestRun("baseline", "finished", now, &v1alpha1.TestRunResults{
+		ThresholdsPassed: true,
+		Metrics:          map[string]string{RegressionMetric: "200"},
+	})
+
+	tests := []struct {
+		name     string
+		k6       v1alpha1.TestRun
+		baseline *v1alpha1.TestRun
+		phase    string
+		passed   bool
+	}{
+		{
+			name:  "running",
+			k6:    analysisTestRun("cart", "started", now, nil),
+			phase: AnalysisRunning,
+		},
+		{
+			name:  "error",
+			k6:    analysisTestRun("cart", "error", now, nil),
+			phase: AnalysisError,
+		},
+		{
+			name:  "no results",
+			k6:    analysisTestRun("cart", "finished", now, nil),
+			phase: AnalysisError,
+		},
+		{
+			name: "failed thresholds",
+			k6: analysisTestRun("cart", "finished", now, &v1alpha1.TestRunResults{
+				ThresholdsPassed: false,
+				FailedThresholds: []string{"http_req_duration: p(95)<200"},
+			}),
+			phase: AnalysisFailed,
+		},
+		{
+			name: "passed without baseline",
+			k6: analysisTestRun("cart", "finished", now, &v1alpha1.TestRunResults{
+				ThresholdsPassed: true,
+				Metrics:          map[string]string{RegressionMetric: "300"},
+			}),
+			phase:  AnalysisPassed,
+			passed: true,
+		},
+		{
+			name: "passed within regression",
+			k6: analysisTestRun("cart", "finished", now, &v1alpha1.TestRunResults{
+				ThresholdsPassed: true,
+				Metrics:          map[string]string{RegressionMetric: "210"},
+			}),
+			baseline: &baseline,
+			phase:    AnalysisPassed,
+			passed:   true,
+		},
+		{
+			name: "regressed",
+			k6: analysisTestRun("cart", "finished", now, &v1alpha1.TestRunResults{
+				ThresholdsPassed: true,
+				Metrics:          map[string]string{RegressionMetric: "300"},
+			}),
+			baseline: &baseline,
+			phase:    AnalysisFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verdict := NewVerdict(&test.k6, test.baseline, RegressionMetric, DefaultMaxRegression)
+			if verdict.Phase != test.phase || verdict.Passed != test.passed {
+				t.Errorf("NewVerdict returned unexpected verdict: %+v", verdict)
+			}
+		})
+	}
+}
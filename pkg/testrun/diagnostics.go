@@ -0,0 +1,129 @@
+package testrun
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	maxDiagnostics       = 5
+	maxDiagnosticLength  = 256
+	diagnosticsSeparator = "; "
+)
+
+// reasons of waiting containers which are a regular part of pod startup
+var startingReasons = map[string]struct{}{
+	"PodInitializing":   {},
+	"ContainerCreating": {},
+}
+
+var (
+	// URLs may be presigned and carry credentials in their path or query
+	urlPattern = regexp.MustCompile(`(https?://[^/\s'"]+)[^\s'"]*`)
+	// credentials in headers and arguments
+	credentialPattern = regexp.MustCompile(`(?i)\b(token|bearer|password|secret)([=:\s]+)[^\s'",;]+`)
+	spacePattern      = regexp.MustCompile(`\s+`)
+)
+
+// Diagnostics summarizes why pods of a test run are not running, from the
+// status of the pods and the warning events of the pods and their jobs.
+// It is meant for users without access to the cluster, e.g. users of k6 Cloud
+// running the test in a PLZ: the summary is concise and sanitized. Issues shared
+// by several pods are reported once, with the number of pods.
+func Diagnostics(pods []corev1.Pod, events []corev1.Event) []string {
+	var (
+		order  []string
+		counts = make(map[string]map[string]struct{})
+	)
+
+	add := func(object, issue string) {
+		issue = sanitize(issue)
+		if _, ok := counts[issue]; !ok {
+			order = append(order, issue)
+			counts[issue] = make(map[string]struct{})
+		}
+		counts[issue][object] = struct{}{}
+	}
+
+	for _, pod := range pods {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+				add(pod.Name, fmt.Sprintf("%s: %s", cond.Reason, cond.Message))
+			}
+		}
+
+		for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, status := range statuses {
+				if issue, ok := containerIssue(status); ok {
+					add(pod.Name, issue)
+				}
+			}
+		}
+	}
+
+	// events are sorted so that the most frequent issues come first
+	// in case the summary has to be cut
+	sorted := make([]corev1.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Count > sorted[j].Count
+	})
+	for _, event := range sorted {
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		add(event.InvolvedObject.Name, fmt.Sprintf("%s: %s", event.Reason, event.Message))
+	}
+
+	var summary []string
+	for _, issue := range order {
+		if len(summary) == maxDiagnostics {
+			break
+		}
+		if n := len(counts[issue]); n > 1 {
+			issue = fmt.Sprintf("%s (%d pods or jobs)", issue, n)
+		}
+		summary = append(summary, issue)
+	}
+	return summary
+}
+
+// DiagnosticsDetail appends the diagnostics to a message of a test run event.
+func DiagnosticsDetail(msg string, diagnostics []string) string {
+	if len(diagnostics) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s Details: %s", msg, strings.Join(diagnostics, diagnosticsSeparator))
+}
+
+func containerIssue(status corev1.ContainerStatus) (string, bool) {
+	if waiting := status.State.Waiting; waiting != nil {
+		if _, ok := startingReasons[waiting.Reason]; ok || len(waiting.Reason) == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("container %s is waiting: %s: %s", status.Name, waiting.Reason, waiting.Message), true
+	}
+
+	if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+		return fmt.Sprintf("container %s failed: %s (exit code %d): %s",
+			status.Name, terminated.Reason, terminated.ExitCode, terminated.Message), true
+	}
+
+	return "", false
+}
+
+// sanitize removes credentials and paths of URLs from the message and makes it one short line.
+func sanitize(msg string) string {
+	msg = urlPattern.ReplaceAllString(msg, "$1/...")
+	msg = credentialPattern.ReplaceAllString(msg, "$1$2***")
+	msg = strings.TrimSuffix(strings.TrimSpace(spacePattern.ReplaceAllString(msg, " ")), ":")
+
+	if len(msg) > maxDiagnosticLength {
+		msg = msg[:maxDiagnosticLength-3] + "..."
+	}
+	return msg
+}
@@ -0,0 +1,129 @@
+package testrun
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func unschedulablePod(name string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Unschedulable",
+				Message: "0/3 nodes are available: 3 Insufficient cpu.",
+			}},
+		},
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	pods := []corev1.Pod{
+		unschedulablePod("plz-test-1-1"),
+		unschedulablePod("plz-test-1-2"),
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "plz-test-1-3"},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name: "archive-download",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						Reason:   "Error",
+						ExitCode: 22,
+						Message:  "curl: (22) The requested URL returned error: 403\n",
+					}},
+				}},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "k6",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason: "PodInitializing",
+					}},
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "plz-test-1-initializer-abc"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "k6",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason:  "ErrImagePull",
+						Message: `failed to pull image "grafana/k6:0.0.0": not found`,
+					}},
+				}},
+			},
+		},
+	}
+	events := []corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "plz-test-1-1"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "Unschedulable",
+			Message:        "0/3 nodes are available: 3 Insufficient cpu.",
+			Count:          3,
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "plz-test-1-3"},
+			Type:           corev1.EventTypeNormal,
+			Reason:         "Pulled",
+			Message:        "Successfully pulled image",
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Job", Name: "plz-test-1-4"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "FailedCreate",
+			Message:        `Error creating: pods "plz-test-1-4-x" is forbidden: exceeded quota: compute`,
+		},
+	}
+
+	expected := []string{
+		"Unschedulable: 0/3 nodes are available: 3 Insufficient cpu. (2 pods or jobs)",
+		"container archive-download failed: Error (exit code 22): curl: (22) The requested URL returned error: 403",
+		`container k6 is waiting: ErrImagePull: failed to pull image "grafana/k6:0.0.0": not found`,
+		`FailedCreate: Error creating: pods "plz-test-1-4-x" is forbidden: exceeded quota: compute`,
+	}
+
+	if diff := deep.Equal(Diagnostics(pods, events), expected); diff != nil {
+		t.Errorf("Diagnostics returned unexpected summary, diff: %s", diff)
+	}
+}
+
+func TestDiagnosticsLimit(t *testing.T) {
+	var events []corev1.Event
+	for _, reason := range []string{"A", "B", "C", "D", "E", "F"} {
+		events = append(events, corev1.Event{Type: corev1.EventTypeWarning, Reason: reason, Message: "failed"})
+	}
+
+	if n := len(Diagnostics(nil, events)); n != maxDiagnostics {
+		t.Errorf("expected %d diagnostics, got %d", maxDiagnostics, n)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		msg, expected string
+	}{
+		{
+			msg:      "Get \"https://bucket.s3.amazonaws.com/archive.tar?X-Amz-Signature=abc\": dial tcp: i/o timeout",
+			expected: "Get \"https://bucket.s3.amazonaws.com/...\": dial tcp: i/o timeout",
+		},
+		{
+			msg:      "header.Authorization=\"Token abc123\" rejected",
+			expected: "header.Authorization=\"Token ***\" rejected",
+		},
+		{
+			msg:      "multi\nline\tmessage:",
+			expected: "multi line message",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := sanitize(tt.msg); got != tt.expected {
+			t.Errorf("sanitize(%q): expected %q, got %q", tt.msg, tt.expected, got)
+		}
+	}
+}
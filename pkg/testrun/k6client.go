@@ -4,18 +4,32 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/grafana/k6-operator/pkg/types"
+	k6api "go.k6.io/k6/api/v1"
 	k6Client "go.k6.io/k6/api/v1/client"
 )
 
+// DefaultPort is the port of the REST API of the runners.
+const DefaultPort = 6565
+
+// address returns the address of the REST API of a runner. Hostnames of
+// Services get the default port; addresses with a port are kept as is.
+func address(hostname string) string {
+	if _, _, err := net.SplitHostPort(hostname); err == nil {
+		return hostname
+	}
+	return net.JoinHostPort(hostname, strconv.Itoa(DefaultPort))
+}
+
 // This will probably be removed once distributed mode in k6 is implemented.
 
 func RunSetup(ctx context.Context, hostname string) (_ json.RawMessage, err error) {
-	c, err := k6Client.New(fmt.Sprintf("%v:6565", hostname), k6Client.WithHTTPClient(&http.Client{
+	c, err := k6Client.New(address(hostname), k6Client.WithHTTPClient(&http.Client{
 		Timeout: 0,
 	}))
 	if err != nil {
@@ -39,7 +53,7 @@ func RunSetup(ctx context.Context, hostname string) (_ json.RawMessage, err erro
 
 func SetSetupData(ctx context.Context, hostnames []string, data json.RawMessage) (err error) {
 	for _, hostname := range hostnames {
-		c, err := k6Client.New(fmt.Sprintf("%v:6565", hostname), k6Client.WithHTTPClient(&http.Client{
+		c, err := k6Client.New(address(hostname), k6Client.WithHTTPClient(&http.Client{
 			Timeout: 0,
 		}))
 		if err != nil {
@@ -59,7 +73,7 @@ func RunTeardown(ctx context.Context, hostnames []string) (err error) {
 		return errors.New("no k6 Service is available to run teardown")
 	}
 
-	c, err := k6Client.New(fmt.Sprintf("%v:6565", hostnames[0]), k6Client.WithHTTPClient(&http.Client{
+	c, err := k6Client.New(address(hostnames[0]), k6Client.WithHTTPClient(&http.Client{
 		Timeout: 0,
 	}))
 	if err != nil {
@@ -68,3 +82,108 @@ func RunTeardown(ctx context.Context, hostnames []string) (err error) {
 
 	return c.CallAPI(ctx, "POST", &url.URL{Path: "/v1/teardown"}, nil, nil)
 }
+
+// GetResults retrieves metrics and checks collected so far by a single runner.
+func GetResults(ctx context.Context, hostname string) (*types.RunnerResults, error) {
+	c, err := k6Client.New(address(hostname), k6Client.WithHTTPClient(&http.Client{
+		Timeout: 0,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := c.Metrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups types.GroupsAPIResponse
+	if err = c.CallAPI(ctx, "GET", &url.URL{Path: "/v1/groups"}, nil, &groups); err != nil {
+		return nil, err
+	}
+
+	return &types.RunnerResults{
+		Hostname: hostname,
+		Metrics:  metrics,
+		Groups:   groups.Groups(),
+	}, nil
+}
+
+// GetStatus retrieves the execution status of a single runner.
+func GetStatus(ctx context.Context, hostname string) (k6api.Status, error) {
+	c, err := k6Client.New(address(hostname), k6Client.WithHTTPClient(&http.Client{
+		Timeout: 0,
+	}))
+	if err != nil {
+		return k6api.Status{}, err
+	}
+
+	return c.Status(ctx)
+}
+
+// SetPaused pauses or resumes execution on the runners. Runners that are
+// already in the requested state are skipped.
+func SetPaused(ctx context.Context, hostnames []string, paused bool) error {
+	for _, hostname := range hostnames {
+		c, err := k6Client.New(address(hostname), k6Client.WithHTTPClient(&http.Client{
+			Timeout: 0,
+		}))
+		if err != nil {
+			return err
+		}
+
+		status, err := c.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if status.Paused.Bool == paused {
+			continue
+		}
+
+		if err = c.CallAPI(ctx, "PATCH", &url.URL{Path: "/v1/status"}, statusRequest(paused, false), nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StopRunners stops execution on the runners. Runners that are already
+// stopped are skipped.
+func StopRunners(ctx context.Context, hostnames []string) error {
+	for _, hostname := range hostnames {
+		c, err := k6Client.New(address(hostname), k6Client.WithHTTPClient(&http.Client{
+			Timeout: 0,
+		}))
+		if err != nil {
+			return err
+		}
+
+		status, err := c.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if status.Stopped {
+			continue
+		}
+
+		if err = c.CallAPI(ctx, "PATCH", &url.URL{Path: "/v1/status"}, statusRequest(false, true), nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func statusRequest(paused, stopped bool) types.StatusAPIRequest {
+	return types.StatusAPIRequest{
+		Data: types.StatusAPIRequestData{
+			Type: "status",
+			ID:   "default",
+			Attributes: types.StatusAPIRequestDataAttributes{
+				Paused:  paused,
+				Stopped: stopped,
+			},
+		},
+	}
+}
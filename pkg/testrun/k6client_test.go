@@ -0,0 +1,15 @@
+package testrun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_address(t *testing.T) {
+	assert.Equal(t, "10.0.0.1:6565", address("10.0.0.1"))
+	assert.Equal(t, "test-service-1:6565", address("test-service-1"))
+	assert.Equal(t, "127.0.0.1:6566", address("127.0.0.1:6566"))
+	assert.Equal(t, "[fd00::1]:6565", address("[fd00::1]:6565"))
+	assert.Equal(t, "[fd00::1]:6565", address("fd00::1"))
+}
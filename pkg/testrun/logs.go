@@ -0,0 +1,139 @@
+package testrun
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const maxErrorMessageLength = 1024
+
+// logEntry is a log line of k6 with `--log-format json`.
+type logEntry struct {
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+	Error string `json:"error"`
+}
+
+// LogErrors are error entries found in the logs of a runner.
+type LogErrors struct {
+	Count int32
+	// Messages are distinct error messages in order of appearance.
+	Messages []string
+}
+
+// ScanErrors reads JSON logs of a runner and returns its error entries.
+// Lines that are not JSON log entries, like output of init containers,
+// are skipped. At most maxMessages distinct messages are kept.
+func ScanErrors(r io.Reader, maxMessages int) (*LogErrors, error) {
+	var (
+		result  = &LogErrors{}
+		seen    = make(map[string]struct{})
+		scanner = bufio.NewScanner(r)
+	)
+
+	// stack traces can make lines long
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		var entry logEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Level != "error" {
+			continue
+		}
+
+		result.Count++
+
+		msg := entry.Msg
+		if len(entry.Error) > 0 {
+			msg += ": " + entry.Error
+		}
+		if len(msg) > maxErrorMessageLength {
+			msg = msg[:maxErrorMessageLength] + "..."
+		}
+
+		if _, ok := seen[msg]; ok || len(result.Messages) >= maxMessages {
+			continue
+		}
+		seen[msg] = struct{}{}
+		result.Messages = append(result.Messages, msg)
+	}
+
+	return result, scanner.Err()
+}
+
+// MergeErrorMessages appends distinct messages to the list, keeping
+// at most maxMessages in total.
+func MergeErrorMessages(messages []string, newMessages []string, maxMessages int) []string {
+	seen := make(map[string]struct{}, len(messages))
+	for _, msg := range messages {
+		seen[msg] = struct{}{}
+	}
+
+	for _, msg := range newMessages {
+		if len(messages) >= maxMessages {
+			break
+		}
+		if _, ok := seen[msg]; ok {
+			continue
+		}
+		seen[msg] = struct{}{}
+		messages = append(messages, msg)
+	}
+
+	return messages
+}
+
+// InitializerError extracts error messages from the termination message
+// of a failed initializer. The initializer writes k6 logs in text format, so
+// `msg` and `error` values of the entries are used. Lines that are not k6
+// log entries are kept as is.
+func InitializerError(terminationMessage string) string {
+	var messages []string
+	for _, line := range strings.Split(terminationMessage, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		msg, ok := logfmtValue(line, "msg")
+		if !ok {
+			messages = append(messages, line)
+			continue
+		}
+		if err, ok := logfmtValue(line, "error"); ok {
+			msg += ": " + err
+		}
+		messages = append(messages, msg)
+	}
+
+	msg := strings.Join(messages, "\n")
+	if len(msg) > maxErrorMessageLength {
+		msg = msg[:maxErrorMessageLength] + "..."
+	}
+	return msg
+}
+
+// logfmtValue returns the value of the key in a logfmt line, as written by k6.
+func logfmtValue(line, key string) (string, bool) {
+	i := strings.Index(" "+line, " "+key+"=")
+	if i < 0 {
+		return "", false
+	}
+	value := line[i+len(key)+1:]
+
+	if strings.HasPrefix(value, `"`) {
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return "", false
+		}
+		unquoted, err := strconv.Unquote(quoted)
+		return unquoted, err == nil
+	}
+
+	if end := strings.IndexByte(value, ' '); end >= 0 {
+		value = value[:end]
+	}
+	return value, true
+}
@@ -0,0 +1,63 @@
+package testrun
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func Test_ScanErrors(t *testing.T) {
+	logs := strings.Join([]string{
+		`+ curl -o /test/archive.tar https://example.com`,
+		`{"level":"info","msg":"started","time":"2024-01-01T00:00:00Z"}`,
+		`{"level":"error","msg":"Uncaught (in promise) ReferenceError: x is not defined\n\tat default (file:///test/test.js:5:2(3))","time":"2024-01-01T00:00:01Z"}`,
+		`{"level":"error","msg":"Uncaught (in promise) ReferenceError: x is not defined\n\tat default (file:///test/test.js:5:2(3))","time":"2024-01-01T00:00:02Z"}`,
+		`{"level":"error","msg":"Request Failed","error":"dial tcp: lookup wrong.host: no such host","time":"2024-01-01T00:00:03Z"}`,
+		`{"level":"error","msg":"third distinct error","time":"2024-01-01T00:00:04Z"}`,
+	}, "\n")
+
+	result, err := ScanErrors(strings.NewReader(logs), 2)
+	if err != nil {
+		t.Fatalf("ScanErrors returned unexpected error: %v", err)
+	}
+
+	expected := &LogErrors{
+		Count: 4,
+		Messages: []string{
+			"Uncaught (in promise) ReferenceError: x is not defined\n\tat default (file:///test/test.js:5:2(3))",
+			"Request Failed: dial tcp: lookup wrong.host: no such host",
+		},
+	}
+
+	if diff := deep.Equal(expected, result); diff != nil {
+		t.Errorf("ScanErrors returned unexpected data, diff: %s", diff)
+	}
+}
+
+func Test_MergeErrorMessages(t *testing.T) {
+	messages := MergeErrorMessages([]string{"a", "b"}, []string{"b", "c", "d"}, 3)
+
+	if diff := deep.Equal([]string{"a", "b", "c"}, messages); diff != nil {
+		t.Errorf("MergeErrorMessages returned unexpected data, diff: %s", diff)
+	}
+}
+
+func Test_InitializerError(t *testing.T) {
+	message := strings.Join([]string{
+		`time="2024-01-01T00:00:00Z" level=error msg="file:///test/test.js: Line 3:7 Unexpected token ; (and 1 more errors)" hint="script exception"`,
+		`time="2024-01-01T00:00:00Z" level=error msg="could not initialize" error="unknown module: k6/x/sql"`,
+		`LocalFile not found exiting...`,
+		``,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"file:///test/test.js: Line 3:7 Unexpected token ; (and 1 more errors)",
+		"could not initialize: unknown module: k6/x/sql",
+		"LocalFile not found exiting...",
+	}, "\n")
+
+	if diff := deep.Equal(expected, InitializerError(message)); diff != nil {
+		t.Errorf("InitializerError returned unexpected data, diff: %s", diff)
+	}
+}
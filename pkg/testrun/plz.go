@@ -15,6 +15,32 @@ func TestName(testRunId string) string {
 	return fmt.Sprintf("plz-test-%s", testRunId)
 }
 
+// EnvSecretName returns the name of the Secret with the environment of a PLZ test run.
+func EnvSecretName(testRunId string) string {
+	return fmt.Sprintf("%s-env", TestName(testRunId))
+}
+
+// NewPLZEnvSecret builds the Secret with the environment configured in
+// k6 Cloud for the runners of the PLZ test run. It returns nil if there
+// is no such environment.
+func NewPLZEnvSecret(k6 *v1alpha1.TestRun, trData *cloud.TestRunData) *corev1.Secret {
+	if len(trData.Environment) == 0 {
+		return nil
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EnvSecretName(trData.TestRunID()),
+			Namespace: k6.Namespace,
+			Labels: map[string]string{
+				"app":   "k6",
+				"k6_cr": k6.Name,
+			},
+		},
+		StringData: trData.Environment,
+	}
+}
+
 // ingestURL is a temp hack
 func NewPLZTestRun(plz *v1alpha1.PrivateLoadZone, token string, trData *cloud.TestRunData, ingestUrl string) *v1alpha1.TestRun {
 	volume := corev1.Volume{
@@ -34,10 +60,26 @@ func NewPLZTestRun(plz *v1alpha1.PrivateLoadZone, token string, trData *cloud.Te
 		volumeMount,
 	)
 
-	envVars := append(trData.EnvVars(), corev1.EnvVar{
+	envVars := []corev1.EnvVar{{
 		Name:  "K6_CLOUD_HOST",
 		Value: ingestUrl,
-	})
+	}}
+
+	// the environment may contain secrets: values are stored in a Secret
+	// created together with the test run and only names are in the spec
+	for _, name := range trData.Environment.Names() {
+		envVars = append(envVars, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: EnvSecretName(trData.TestRunID()),
+					},
+					Key: name,
+				},
+			},
+		})
+	}
 
 	return &v1alpha1.TestRun{
 		ObjectMeta: metav1.ObjectMeta{
@@ -76,7 +118,8 @@ func NewPLZTestRun(plz *v1alpha1.PrivateLoadZone, token string, trData *cloud.Te
 				plz.Name,
 				trData.TestRunID(),
 				token),
-			Cleanup: v1alpha1.Cleanup("post"),
+			Cleanup:         v1alpha1.Cleanup("post"),
+			SecurityProfile: plz.Spec.SecurityProfile,
 
 			TestRunID: trData.TestRunID(),
 			Token:     plz.Spec.Token,
@@ -74,7 +74,7 @@ func Test_NewPLZTestRun(t *testing.T) {
 		someRunnerImage = "grafana/k6:0.52.0"
 		someInstances   = 10
 		someArchiveURL  = "https://foo.s3.amazonaws.com"
-		someEnvVars     = map[string]string{
+		someEnvVars     = cloud.Environment{
 			"ENV": "VALUE",
 			"foo": "bar",
 		}
@@ -117,18 +117,28 @@ func Test_NewPLZTestRun(t *testing.T) {
 	cloudFieldsTestRun.Spec.Parallelism = int32(someInstances)
 
 	cloudEnvVarsTestRun = cloudFieldsTestRun // build up on top of cloud fields case
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: EnvSecretName(fmt.Sprintf("%d", someTestRunID)),
+				},
+				Key: key,
+			},
+		}
+	}
 	cloudEnvVarsTestRun.Spec.Runner.Env = []corev1.EnvVar{
 		{
-			Name:  "ENV",
-			Value: "VALUE",
+			Name:  "K6_CLOUD_HOST",
+			Value: mainIngest,
 		},
 		{
-			Name:  "foo",
-			Value: "bar",
+			Name:      "ENV",
+			ValueFrom: secretKeyRef("ENV"),
 		},
 		{
-			Name:  "K6_CLOUD_HOST",
-			Value: mainIngest,
+			Name:      "foo",
+			ValueFrom: secretKeyRef("foo"),
 		},
 	}
 
@@ -235,3 +245,39 @@ func Test_NewPLZTestRun(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewPLZEnvSecret(t *testing.T) {
+	k6 := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TestName("6543"),
+			Namespace: "some-ns",
+		},
+	}
+	trData := &cloud.TestRunData{
+		TestRunId: 6543,
+		LZConfig: cloud.LZConfig{
+			Environment: cloud.Environment{"API_KEY": "secret"},
+		},
+	}
+
+	secret := NewPLZEnvSecret(k6, trData)
+	expected := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "plz-test-6543-env",
+			Namespace: "some-ns",
+			Labels: map[string]string{
+				"app":   "k6",
+				"k6_cr": "plz-test-6543",
+			},
+		},
+		StringData: map[string]string{"API_KEY": "secret"},
+	}
+	if diff := deep.Equal(secret, expected); diff != nil {
+		t.Errorf("NewPLZEnvSecret returned unexpected data, diff: %s", diff)
+	}
+
+	trData.Environment = nil
+	if secret := NewPLZEnvSecret(k6, trData); secret != nil {
+		t.Errorf("expected no Secret without environment, got %v", secret)
+	}
+}
@@ -0,0 +1,95 @@
+package testrun
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RunnerIndexAnnotation is set on runner jobs to the index of their runner,
+// which is also their `instance_id` tag. The command of the runner may be
+// wrapped in a shell, so the tag can't be read from it.
+const RunnerIndexAnnotation = "k6.io/runner-index"
+
+// ReplacementTimeout is how long a replacement is given to start executing
+// after the loss of the runner, e.g. while its pod waits for a node.
+const ReplacementTimeout = 5 * time.Minute
+
+// RunnerIndex returns the index of the runner of the job.
+func RunnerIndex(job *batchv1.Job) (int, bool) {
+	value, ok := job.Annotations[RunnerIndexAnnotation]
+	if !ok {
+		return 0, false
+	}
+	index, err := strconv.Atoi(value)
+	return index, err == nil
+}
+
+// Lost tells if the runner of the job was lost, e.g. to an eviction or to a
+// node going down, rather than finished by k6. A failed job is lost unless
+// k6 exited by itself in one of its pods: k6 exit codes are below 128, while
+// killed containers exit with 128 plus the signal number. Runners killed for
+// running out of memory are not lost: their replacements would be as well.
+func Lost(job *batchv1.Job, pods []corev1.Pod) bool {
+	if !jobFailed(job) {
+		return false
+	}
+
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != "k6" || cs.State.Terminated == nil {
+				continue
+			}
+			if cs.State.Terminated.ExitCode < 128 || cs.State.Terminated.Reason == "OOMKilled" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func jobFailed(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplacementName returns the name of the job of the replacement of the runner.
+// The name is the same for the same number of earlier replacements of the
+// runner, so that a replacement is created only once.
+func ReplacementName(k6 *v1alpha1.TestRun, index int) string {
+	n := 1
+	for _, replacement := range k6.GetStatus().ReplacedRunners {
+		if replacement.Runner == index {
+			n++
+		}
+	}
+	return fmt.Sprintf("%s-%d-replacement-%d", k6.NamespacedName().Name, index, n)
+}
+
+// Replaced tells if the runner of the job was lost and replaced.
+func Replaced(k6 *v1alpha1.TestRun, jobName string) bool {
+	for _, replacement := range k6.GetStatus().ReplacedRunners {
+		if replacement.LostJob == jobName {
+			return true
+		}
+	}
+	return false
+}
+
+// Replacement tells if the job replaces a lost runner.
+func Replacement(k6 *v1alpha1.TestRun, jobName string) bool {
+	for _, replacement := range k6.GetStatus().ReplacedRunners {
+		if replacement.Job == jobName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package testrun
+
+import (
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_RunnerIndex(t *testing.T) {
+	job := &batchv1.Job{}
+	_, ok := RunnerIndex(job)
+	assert.False(t, ok)
+
+	job.Annotations = map[string]string{RunnerIndexAnnotation: "3"}
+	index, ok := RunnerIndex(job)
+	assert.True(t, ok)
+	assert.Equal(t, 3, index)
+
+	job.Annotations[RunnerIndexAnnotation] = "three"
+	_, ok = RunnerIndex(job)
+	assert.False(t, ok)
+}
+
+func Test_Lost(t *testing.T) {
+	failed := &batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+		Type:   batchv1.JobFailed,
+		Status: corev1.ConditionTrue,
+	}}}}
+	terminated := func(exitCode int32, reason string) corev1.Pod {
+		return corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name: "k6",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: exitCode,
+				Reason:   reason,
+			}},
+		}}}}
+	}
+
+	assert.False(t, Lost(&batchv1.Job{}, nil), "running job")
+	assert.True(t, Lost(failed, nil), "deleted pod")
+	assert.True(t, Lost(failed, []corev1.Pod{terminated(137, "Error")}), "killed runner")
+	assert.False(t, Lost(failed, []corev1.Pod{terminated(137, "OOMKilled")}), "runner out of memory")
+	assert.False(t, Lost(failed, []corev1.Pod{terminated(99, "Error")}), "failed thresholds")
+}
+
+func Test_Replacements(t *testing.T) {
+	k6 := &v1alpha1.TestRun{ObjectMeta: metav1.ObjectMeta{Name: "soak"}}
+	assert.Equal(t, "soak-2-replacement-1", ReplacementName(k6, 2))
+
+	k6.Status.ReplacedRunners = []v1alpha1.RunnerReplacement{{
+		Runner:  2,
+		LostJob: "soak-2",
+		Job:     "soak-2-replacement-1",
+	}}
+	assert.Equal(t, "soak-2-replacement-2", ReplacementName(k6, 2))
+	assert.Equal(t, "soak-1-replacement-1", ReplacementName(k6, 1))
+
+	assert.True(t, Replaced(k6, "soak-2"))
+	assert.False(t, Replaced(k6, "soak-2-replacement-1"))
+	assert.True(t, Replacement(k6, "soak-2-replacement-1"))
+	assert.False(t, Replacement(k6, "soak-2"))
+}
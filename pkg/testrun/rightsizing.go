@@ -0,0 +1,199 @@
+package testrun
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// requests cover the average CPU and the peak memory, limits cover the peaks
+	cpuRequestHeadroom = 1.2
+	memRequestHeadroom = 1.2
+	limitHeadroom      = 1.5
+
+	minCPUMilli = 100
+	minMemory   = 64 << 20
+)
+
+// UsageKey identifies TestRuns that can share recommendations: TestRuns of
+// the same trigger or, without a trigger, of the same script.
+func UsageKey(k6 *v1alpha1.TestRun, scriptRevision string) string {
+	if trigger, ok := k6.Labels[v1alpha1.TriggerLabel]; ok {
+		return "trigger/" + trigger
+	}
+	if len(scriptRevision) > 0 {
+		return "script/" + scriptRevision
+	}
+
+	script := k6.GetSpec().Script
+	switch {
+	case len(script.VolumeClaim.Name) > 0:
+		return fmt.Sprintf("volumeClaim/%s/%s", script.VolumeClaim.Name, script.VolumeClaim.File)
+	case len(script.LocalFile) > 0:
+		return "localFile/" + script.LocalFile
+	}
+	return ""
+}
+
+// AddUsageSample adds the current usage of the runner pods to the averages
+// and peaks of each runner. Usage of a pod is the sum of its containers.
+func AddUsageSample(usage *v1alpha1.ResourceUsage, pods []types.PodMetrics, now metav1.Time) {
+	usage.Samples++
+	usage.LastSampleTime = &now
+
+	for _, pod := range pods {
+		var cpu, mem int64
+		for _, c := range pod.Containers {
+			cpu += c.Usage.Cpu().MilliValue()
+			mem += c.Usage.Memory().Value()
+		}
+
+		var runner *v1alpha1.RunnerUsage
+		for i := range usage.Runners {
+			if usage.Runners[i].Pod == pod.Name {
+				runner = &usage.Runners[i]
+				break
+			}
+		}
+		if runner == nil {
+			usage.Runners = append(usage.Runners, v1alpha1.RunnerUsage{Pod: pod.Name})
+			runner = &usage.Runners[len(usage.Runners)-1]
+		}
+
+		n := int64(runner.Samples)
+		runner.Samples++
+
+		runner.AvgCPU = *resource.NewMilliQuantity((runner.AvgCPU.MilliValue()*n+cpu)/(n+1), resource.DecimalSI)
+		runner.AvgMemory = *resource.NewQuantity((runner.AvgMemory.Value()*n+mem)/(n+1), resource.BinarySI)
+		if cpu > runner.PeakCPU.MilliValue() {
+			runner.PeakCPU = *resource.NewMilliQuantity(cpu, resource.DecimalSI)
+		}
+		if mem > runner.PeakMemory.Value() {
+			runner.PeakMemory = *resource.NewQuantity(mem, resource.BinarySI)
+		}
+	}
+
+	sort.Slice(usage.Runners, func(i, j int) bool {
+		return usage.Runners[i].Pod < usage.Runners[j].Pod
+	})
+}
+
+// VUsPerRunner is the number of VUs of the busiest runner.
+func VUsPerRunner(maxVUs uint64, parallelism int32) int64 {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	vus := (int64(maxVUs) + int64(parallelism) - 1) / int64(parallelism)
+	if vus < 1 {
+		vus = 1
+	}
+	return vus
+}
+
+// UsagePerVU divides usage of the busiest runner, i.e. the highest value of
+// each resource across runners, by the number of VUs per runner.
+// It returns nil if there are no samples.
+func UsagePerVU(usage *v1alpha1.ResourceUsage, vusPerRunner int64) *v1alpha1.RunnerUsage {
+	if len(usage.Runners) == 0 || vusPerRunner < 1 {
+		return nil
+	}
+
+	var peakCPU, avgCPU, peakMem, avgMem int64
+	for _, r := range usage.Runners {
+		peakCPU = max(peakCPU, r.PeakCPU.MilliValue())
+		avgCPU = max(avgCPU, r.AvgCPU.MilliValue())
+		peakMem = max(peakMem, r.PeakMemory.Value())
+		avgMem = max(avgMem, r.AvgMemory.Value())
+	}
+
+	// CPU per VU is often below a millicore
+	return &v1alpha1.RunnerUsage{
+		PeakCPU:    *resource.NewScaledQuantity(peakCPU*1000/vusPerRunner, resource.Micro),
+		AvgCPU:     *resource.NewScaledQuantity(avgCPU*1000/vusPerRunner, resource.Micro),
+		PeakMemory: *resource.NewQuantity(peakMem/vusPerRunner, resource.BinarySI),
+		AvgMemory:  *resource.NewQuantity(avgMem/vusPerRunner, resource.BinarySI),
+	}
+}
+
+// RecommendResources scales usage per VU to the number of VUs per runner,
+// with headroom. CPU is rounded up to 10m and memory to 1Mi.
+func RecommendResources(perVU *v1alpha1.RunnerUsage, vusPerRunner int64) corev1.ResourceRequirements {
+	scale := func(q resource.Quantity, headroom float64, scaledTo resource.Scale) int64 {
+		return int64(float64(q.ScaledValue(scaledTo)) * float64(vusPerRunner) * headroom)
+	}
+	roundUp := func(v, unit, minimum int64) int64 {
+		v = (v + unit - 1) / unit * unit
+		return max(v, minimum)
+	}
+
+	// micro cores to milli cores
+	cpu := func(q resource.Quantity, headroom float64) resource.Quantity {
+		return *resource.NewMilliQuantity(roundUp(scale(q, headroom, resource.Micro)/1000, 10, minCPUMilli), resource.DecimalSI)
+	}
+	mem := func(q resource.Quantity, headroom float64) resource.Quantity {
+		return *resource.NewQuantity(roundUp(scale(q, headroom, 0), 1<<20, minMemory), resource.BinarySI)
+	}
+
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    cpu(perVU.AvgCPU, cpuRequestHeadroom),
+			corev1.ResourceMemory: mem(perVU.PeakMemory, memRequestHeadroom),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    cpu(perVU.PeakCPU, limitHeadroom),
+			corev1.ResourceMemory: mem(perVU.PeakMemory, limitHeadroom),
+		},
+	}
+}
+
+// LatestRecommendation returns the latest finished TestRun with the key
+// and usage per VU, or nil.
+func LatestRecommendation(key string, candidates []v1alpha1.TestRun) *v1alpha1.TestRun {
+	var latest *v1alpha1.TestRun
+	for i := range candidates {
+		c := &candidates[i]
+		usage := c.Status.ResourceUsage
+		if c.Status.Stage != "finished" || usage == nil || usage.Key != key || usage.PerVU == nil {
+			continue
+		}
+		if latest == nil || latest.CreationTimestamp.Before(&c.CreationTimestamp) {
+			latest = c
+		}
+	}
+	return latest
+}
+
+// MergeResources sets CPU and memory of the recommendation in the resources
+// of the spec. Other resources of the spec, e.g. extended resources, are kept.
+func MergeResources(spec, recommended corev1.ResourceRequirements) corev1.ResourceRequirements {
+	merged := *spec.DeepCopy()
+	merge := func(list, from corev1.ResourceList) corev1.ResourceList {
+		if list == nil {
+			list = corev1.ResourceList{}
+		}
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if q, ok := from[name]; ok {
+				list[name] = q
+			}
+		}
+		return list
+	}
+	merged.Requests = merge(merged.Requests, recommended.Requests)
+	merged.Limits = merge(merged.Limits, recommended.Limits)
+	return merged
+}
+
+// RunnerResources returns the resources of the runners: the applied
+// recommendation if there is one, otherwise the resources of the spec.
+func RunnerResources(k6 *v1alpha1.TestRun) corev1.ResourceRequirements {
+	if usage := k6.GetStatus().ResourceUsage; usage != nil && usage.Applied != nil {
+		return *usage.Applied
+	}
+	return k6.GetSpec().Runner.Resources
+}
@@ -0,0 +1,202 @@
+package testrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func podMetrics(name, cpu, mem string) types.PodMetrics {
+	return types.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Containers: []types.ContainerMetrics{{
+			Name: "k6",
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(mem),
+			},
+		}},
+	}
+}
+
+func Test_UsageKey(t *testing.T) {
+	k6 := &v1alpha1.TestRun{}
+	if key := UsageKey(k6, "0123456789abcdef"); key != "script/0123456789abcdef" {
+		t.Errorf("UsageKey returned unexpected key: %s", key)
+	}
+
+	k6.Spec.Script.LocalFile = "/test/test.js"
+	if key := UsageKey(k6, ""); key != "localFile//test/test.js" {
+		t.Errorf("UsageKey returned unexpected key: %s", key)
+	}
+
+	k6.Labels = map[string]string{v1alpha1.TriggerLabel: "nightly"}
+	if key := UsageKey(k6, "0123456789abcdef"); key != "trigger/nightly" {
+		t.Errorf("UsageKey returned unexpected key: %s", key)
+	}
+}
+
+func Test_AddUsageSample(t *testing.T) {
+	usage := &v1alpha1.ResourceUsage{}
+
+	AddUsageSample(usage, []types.PodMetrics{
+		podMetrics("test-2", "200m", "100Mi"),
+		podMetrics("test-1", "400m", "200Mi"),
+	}, metav1.Now())
+	AddUsageSample(usage, []types.PodMetrics{
+		podMetrics("test-1", "800m", "100Mi"),
+		podMetrics("test-2", "200m", "300Mi"),
+	}, metav1.Now())
+
+	if usage.Samples != 2 || len(usage.Runners) != 2 {
+		t.Fatalf("AddUsageSample returned unexpected usage: %+v", usage)
+	}
+
+	r := usage.Runners[0]
+	if r.Pod != "test-1" || r.Samples != 2 ||
+		r.PeakCPU.String() != "800m" || r.AvgCPU.String() != "600m" ||
+		r.PeakMemory.String() != "200Mi" || r.AvgMemory.String() != "150Mi" {
+		t.Errorf("AddUsageSample returned unexpected usage of the runner: %+v", r)
+	}
+}
+
+func Test_RecommendResources(t *testing.T) {
+	if vus := VUsPerRunner(101, 4); vus != 26 {
+		t.Errorf("VUsPerRunner returned %d", vus)
+	}
+
+	usage := &v1alpha1.ResourceUsage{
+		Runners: []v1alpha1.RunnerUsage{
+			{
+				Pod:        "test-1",
+				PeakCPU:    resource.MustParse("1"),
+				AvgCPU:     resource.MustParse("500m"),
+				PeakMemory: resource.MustParse("400Mi"),
+				AvgMemory:  resource.MustParse("300Mi"),
+			},
+			{
+				Pod:        "test-2",
+				PeakCPU:    resource.MustParse("800m"),
+				AvgCPU:     resource.MustParse("600m"),
+				PeakMemory: resource.MustParse("200Mi"),
+				AvgMemory:  resource.MustParse("100Mi"),
+			},
+		},
+	}
+
+	perVU := UsagePerVU(usage, 100)
+	if perVU.PeakCPU.String() != "10m" || perVU.AvgCPU.String() != "6m" || perVU.PeakMemory.String() != "4Mi" {
+		t.Errorf("UsagePerVU returned unexpected usage: %+v", perVU)
+	}
+
+	recommended := RecommendResources(perVU, 200)
+	expected := map[string]string{
+		"requests.cpu":    "1440m",
+		"requests.memory": "960Mi",
+		"limits.cpu":      "3",
+		"limits.memory":   "1200Mi",
+	}
+	actual := map[string]string{
+		"requests.cpu":    recommended.Requests.Cpu().String(),
+		"requests.memory": recommended.Requests.Memory().String(),
+		"limits.cpu":      recommended.Limits.Cpu().String(),
+		"limits.memory":   recommended.Limits.Memory().String(),
+	}
+	for k, v := range expected {
+		if actual[k] != v {
+			t.Errorf("RecommendResources returned unexpected %s: %s != %s", k, actual[k], v)
+		}
+	}
+
+	minimal := RecommendResources(perVU, 1)
+	if minimal.Requests.Cpu().String() != "100m" || minimal.Requests.Memory().String() != "64Mi" {
+		t.Errorf("RecommendResources returned resources below the minimum: %+v", minimal)
+	}
+}
+
+func Test_LatestRecommendation(t *testing.T) {
+	now := time.Now()
+	testRun := func(name, stage, key string, created time.Time, perVU bool) v1alpha1.TestRun {
+		k6 := v1alpha1.TestRun{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Status: v1alpha1.TestRunStatus{
+				Stage:         v1alpha1.Stage(stage),
+				ResourceUsage: &v1alpha1.ResourceUsage{Key: key},
+			},
+		}
+		if perVU {
+			k6.Status.ResourceUsage.PerVU = &v1alpha1.RunnerUsage{}
+		}
+		return k6
+	}
+
+	candidates := []v1alpha1.TestRun{
+		testRun("old", "finished", "trigger/nightly", now.Add(-2*time.Hour), true),
+		testRun("previous", "finished", "trigger/nightly", now.Add(-time.Hour), true),
+		testRun("other", "finished", "trigger/weekly", now, true),
+		testRun("running", "started", "trigger/nightly", now, false),
+	}
+
+	if latest := LatestRecommendation("trigger/nightly", candidates); latest == nil || latest.Name != "previous" {
+		t.Errorf("LatestRecommendation returned unexpected TestRun: %v", latest)
+	}
+	if latest := LatestRecommendation("trigger/hourly", candidates); latest != nil {
+		t.Errorf("LatestRecommendation returned unexpected TestRun: %s", latest.Name)
+	}
+}
+
+func Test_MergeResources(t *testing.T) {
+	gpu := corev1.ResourceName("nvidia.com/gpu")
+	spec := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("4"),
+			gpu:                resource.MustParse("1"),
+		},
+		Limits: corev1.ResourceList{
+			gpu: resource.MustParse("1"),
+		},
+	}
+	recommended := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1440m"),
+			corev1.ResourceMemory: resource.MustParse("960Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("3"),
+			corev1.ResourceMemory: resource.MustParse("1200Mi"),
+		},
+	}
+
+	merged := MergeResources(spec, recommended)
+	expected := map[string]string{
+		"requests.cpu":    "1440m",
+		"requests.memory": "960Mi",
+		"requests.gpu":    "1",
+		"limits.cpu":      "3",
+		"limits.memory":   "1200Mi",
+		"limits.gpu":      "1",
+	}
+	gpuRequest, gpuLimit := merged.Requests[gpu], merged.Limits[gpu]
+	actual := map[string]string{
+		"requests.cpu":    merged.Requests.Cpu().String(),
+		"requests.memory": merged.Requests.Memory().String(),
+		"requests.gpu":    gpuRequest.String(),
+		"limits.cpu":      merged.Limits.Cpu().String(),
+		"limits.memory":   merged.Limits.Memory().String(),
+		"limits.gpu":      gpuLimit.String(),
+	}
+	for k, v := range expected {
+		if actual[k] != v {
+			t.Errorf("MergeResources returned unexpected %s: %s != %s", k, actual[k], v)
+		}
+	}
+
+	if spec.Requests.Cpu().String() != "4" {
+		t.Errorf("MergeResources changed resources of the spec: %+v", spec)
+	}
+}
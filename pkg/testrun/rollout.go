@@ -0,0 +1,108 @@
+package testrun
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewWorkload returns an empty object of the workload kind.
+func NewWorkload(kind string) (client.Object, error) {
+	switch kind {
+	case "Deployment":
+		return &appsv1.Deployment{}, nil
+	case "StatefulSet":
+		return &appsv1.StatefulSet{}, nil
+	}
+	return nil, fmt.Errorf("unsupported kind of workload: %s", kind)
+}
+
+// RolloutImage returns the image of the container of the workload.
+func RolloutImage(workload client.Object, rollout *v1alpha1.RolloutTrigger) (string, error) {
+	var containers []corev1.Container
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		containers = w.Spec.Template.Spec.Containers
+	case *appsv1.StatefulSet:
+		containers = w.Spec.Template.Spec.Containers
+	}
+
+	for _, c := range containers {
+		if len(rollout.Container) == 0 || c.Name == rollout.Container {
+			return c.Image, nil
+		}
+	}
+	return "", fmt.Errorf("no container %q in %s %s", rollout.Container, rollout.Kind, rollout.Name)
+}
+
+// RolloutRevision identifies the current rollout of the workload: it is
+// either the image of the container or the generation of the workload.
+func RolloutRevision(workload client.Object, rollout *v1alpha1.RolloutTrigger) (string, error) {
+	if rollout.ChangeOn == v1alpha1.GenerationChange {
+		return strconv.FormatInt(workload.GetGeneration(), 10), nil
+	}
+	return RolloutImage(workload, rollout)
+}
+
+// RolloutComplete tells whether all replicas of the workload are updated
+// and available, with no replicas of the previous revision left.
+func RolloutComplete(workload client.Object) bool {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		replicas := int32(1)
+		if w.Spec.Replicas != nil {
+			replicas = *w.Spec.Replicas
+		}
+		return w.Status.ObservedGeneration >= w.Generation &&
+			w.Status.UpdatedReplicas == replicas &&
+			w.Status.AvailableReplicas == replicas &&
+			w.Status.Replicas == replicas
+
+	case *appsv1.StatefulSet:
+		replicas := int32(1)
+		if w.Spec.Replicas != nil {
+			replicas = *w.Spec.Replicas
+		}
+		return w.Status.ObservedGeneration >= w.Generation &&
+			w.Status.UpdatedReplicas == replicas &&
+			w.Status.AvailableReplicas == replicas &&
+			w.Status.CurrentRevision == w.Status.UpdateRevision
+	}
+	return false
+}
+
+// ImageTag returns the tag of the image, or its digest if there is no tag.
+func ImageTag(image string) string {
+	name, digest, hasDigest := strings.Cut(image, "@")
+
+	// a colon before the last slash is a port of the registry
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		return name[i+1:]
+	}
+	if hasDigest {
+		return digest
+	}
+	return "latest"
+}
+
+// injectImage passes the image of the rollout to the runners.
+func injectImage(spec *v1alpha1.TestRunSpec, image string) {
+	tag := ImageTag(image)
+
+	spec.Runner.Env = append(spec.Runner.Env,
+		corev1.EnvVar{Name: "K6_ROLLOUT_IMAGE", Value: image},
+		corev1.EnvVar{Name: "K6_ROLLOUT_IMAGE_TAG", Value: tag},
+	)
+
+	tagArg := fmt.Sprintf("--tag rollout_image_tag=%s", tag)
+	if len(spec.Arguments) > 0 {
+		spec.Arguments += " " + tagArg
+	} else {
+		spec.Arguments = tagArg
+	}
+}
@@ -0,0 +1,95 @@
+package testrun
+
+import (
+	"testing"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ImageTag(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                            "latest",
+		"nginx:1.27":                       "1.27",
+		"registry.local:5000/shop/cart":    "latest",
+		"registry.local:5000/shop/cart:v2": "v2",
+		"shop/cart@sha256:abcdef":          "sha256:abcdef",
+		"shop/cart:v2@sha256:abcdef":       "v2",
+	}
+
+	for image, expected := range tests {
+		assert.Equal(t, expected, ImageTag(image), image)
+	}
+}
+
+func Test_RolloutRevision(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 3},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "proxy", Image: "envoy:v1"},
+						{Name: "app", Image: "shop/cart:v2"},
+					},
+				},
+			},
+		},
+	}
+
+	revision, err := RolloutRevision(deployment, &v1alpha1.RolloutTrigger{})
+	assert.NoError(t, err)
+	assert.Equal(t, "envoy:v1", revision)
+
+	revision, err = RolloutRevision(deployment, &v1alpha1.RolloutTrigger{Container: "app"})
+	assert.NoError(t, err)
+	assert.Equal(t, "shop/cart:v2", revision)
+
+	revision, err = RolloutRevision(deployment, &v1alpha1.RolloutTrigger{ChangeOn: v1alpha1.GenerationChange})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", revision)
+
+	_, err = RolloutRevision(deployment, &v1alpha1.RolloutTrigger{Container: "db"})
+	assert.Error(t, err)
+}
+
+func Test_RolloutComplete(t *testing.T) {
+	replicas := int32(3)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           4,
+			UpdatedReplicas:    3,
+			AvailableReplicas:  3,
+		},
+	}
+	assert.False(t, RolloutComplete(deployment), "old replica is still running")
+
+	deployment.Status.Replicas = 3
+	assert.True(t, RolloutComplete(deployment))
+
+	deployment.Generation = 3
+	assert.False(t, RolloutComplete(deployment), "new generation is not observed yet")
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 2,
+			UpdatedReplicas:    3,
+			AvailableReplicas:  3,
+			CurrentRevision:    "cart-1",
+			UpdateRevision:     "cart-2",
+		},
+	}
+	assert.False(t, RolloutComplete(statefulSet), "update revision is not current yet")
+
+	statefulSet.Status.CurrentRevision = "cart-2"
+	assert.True(t, RolloutComplete(statefulSet))
+}
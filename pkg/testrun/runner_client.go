@@ -0,0 +1,48 @@
+package testrun
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/grafana/k6-operator/pkg/types"
+)
+
+// RunnerClient is the REST API of the runners as used by the TestRun
+// controller. Runners are addressed by the hostnames of their Services.
+type RunnerClient interface {
+	RunSetup(ctx context.Context, hostname string) (json.RawMessage, error)
+	SetSetupData(ctx context.Context, hostnames []string, data json.RawMessage) error
+	RunTeardown(ctx context.Context, hostnames []string) error
+	GetResults(ctx context.Context, hostname string) (*types.RunnerResults, error)
+	SetPaused(ctx context.Context, hostnames []string, paused bool) error
+	StopRunners(ctx context.Context, hostnames []string) error
+}
+
+// RESTClient is the RunnerClient of the k6 REST API.
+type RESTClient struct{}
+
+var _ RunnerClient = RESTClient{}
+
+func (RESTClient) RunSetup(ctx context.Context, hostname string) (json.RawMessage, error) {
+	return RunSetup(ctx, hostname)
+}
+
+func (RESTClient) SetSetupData(ctx context.Context, hostnames []string, data json.RawMessage) error {
+	return SetSetupData(ctx, hostnames, data)
+}
+
+func (RESTClient) RunTeardown(ctx context.Context, hostnames []string) error {
+	return RunTeardown(ctx, hostnames)
+}
+
+func (RESTClient) GetResults(ctx context.Context, hostname string) (*types.RunnerResults, error) {
+	return GetResults(ctx, hostname)
+}
+
+func (RESTClient) SetPaused(ctx context.Context, hostnames []string, paused bool) error {
+	return SetPaused(ctx, hostnames, paused)
+}
+
+func (RESTClient) StopRunners(ctx context.Context, hostnames []string) error {
+	return StopRunners(ctx, hostnames)
+}
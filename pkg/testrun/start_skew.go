@@ -0,0 +1,140 @@
+package testrun
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/grafana/k6-operator/pkg/resources/containers"
+)
+
+// StartOffsets reads the logs of the starter and returns when each runner
+// started, keyed by its hostname, as an offset from the first start request.
+// A runner has started once its start request completed. The starter logs
+// the time each request completed, so the offsets include the time between
+// requests too. If any of the times is missing, e.g. with a custom starter
+// image whose `date` has no nanoseconds, the offsets are the sum of the
+// durations of the requests instead. Runners whose request failed are left
+// out, but the time spent on the request still adds to the offsets.
+func StartOffsets(r io.Reader) (map[string]time.Duration, error) {
+	type request struct {
+		host, code string
+		total      time.Duration
+		end        time.Time
+	}
+
+	var (
+		requests []request
+		withEnd  = true
+		scanner  = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, containers.StartTimingMarker+" ") {
+			continue
+		}
+
+		req := request{}
+		req.host, _ = logfmtValue(line, "host")
+		req.code, _ = logfmtValue(line, "code")
+		total, ok := logfmtValue(line, "total")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(total, 64)
+		if err != nil {
+			continue
+		}
+		req.total = time.Duration(seconds * float64(time.Second))
+
+		end, _ := logfmtValue(line, "end")
+		if req.end, ok = parseEpoch(end); !ok {
+			withEnd = false
+		}
+
+		requests = append(requests, req)
+	}
+
+	offsets := make(map[string]time.Duration)
+	if len(requests) == 0 {
+		return offsets, scanner.Err()
+	}
+
+	var (
+		first   = requests[0].end.Add(-requests[0].total)
+		elapsed time.Duration
+	)
+	for _, req := range requests {
+		elapsed += req.total
+		if len(req.host) == 0 || !strings.HasPrefix(req.code, "2") {
+			continue
+		}
+
+		if withEnd {
+			offsets[req.host] = req.end.Sub(first)
+		} else {
+			offsets[req.host] = elapsed
+		}
+	}
+
+	return offsets, scanner.Err()
+}
+
+// parseEpoch parses seconds since the epoch with up to nanoseconds, e.g. `1718035200.123456789`.
+func parseEpoch(value string) (time.Time, bool) {
+	sec, frac, _ := strings.Cut(value, ".")
+	seconds, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	if len(frac) > 9 {
+		frac = frac[:9]
+	}
+	var nanoseconds int64
+	if len(frac) > 0 {
+		if nanoseconds, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64); err != nil || nanoseconds < 0 {
+			return time.Time{}, false
+		}
+	}
+
+	return time.Unix(seconds, nanoseconds), true
+}
+
+// NewStartSkew summarizes start offsets of the runners. The offsets are keyed
+// by hostnames and names maps the hostnames to the runner jobs. It returns nil
+// if there are no offsets.
+func NewStartSkew(offsets map[string]time.Duration, names map[string]string) (*v1alpha1.StartSkew, time.Duration) {
+	if len(offsets) == 0 {
+		return nil, 0
+	}
+
+	skew := &v1alpha1.StartSkew{Runners: make(map[string]string, len(offsets))}
+
+	first, last := time.Duration(-1), time.Duration(0)
+	for host, offset := range offsets {
+		name := host
+		if n, ok := names[host]; ok {
+			name = n
+		}
+		skew.Runners[name] = offset.String()
+
+		if first < 0 || offset < first {
+			first = offset
+		}
+		if offset > last {
+			last = offset
+		}
+	}
+
+	spread := last - first
+	skew.Min = first.String()
+	skew.Max = last.String()
+	skew.Spread = spread.String()
+
+	return skew, spread
+}
@@ -0,0 +1,78 @@
+package testrun
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StartOffsets(t *testing.T) {
+	logs := `k6-start host=10.0.0.1 code=200 total=0.004000
+k6-start host=10.0.0.2 code=000 total=1.500000
+curl: (7) Failed to connect to 10.0.0.2 port 6565
+k6-start host=10.0.0.3 code=200 total=0.002500
+`
+
+	offsets, err := StartOffsets(strings.NewReader(logs))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"10.0.0.1": 4 * time.Millisecond,
+		"10.0.0.3": 1506500 * time.Microsecond,
+	}, offsets)
+
+	// time between the requests counts with the times of completion
+	logs = `k6-start host=10.0.0.1 code=200 total=0.004000 end=1718035200.104
+k6-start host=10.0.0.2 code=000 total=1.500000 end=1718035201.610
+curl: (7) Failed to connect to 10.0.0.2 port 6565
+k6-start host=10.0.0.3 code=200 total=0.002500 end=1718035201.620000000
+`
+
+	offsets, err = StartOffsets(strings.NewReader(logs))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"10.0.0.1": 4 * time.Millisecond,
+		"10.0.0.3": 1520 * time.Millisecond,
+	}, offsets)
+
+	// `date` without nanoseconds
+	logs = `k6-start host=10.0.0.1 code=200 total=0.004000 end=1718035200.%N
+k6-start host=10.0.0.3 code=200 total=0.002500 end=1718035201.%N
+`
+
+	offsets, err = StartOffsets(strings.NewReader(logs))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"10.0.0.1": 4 * time.Millisecond,
+		"10.0.0.3": 6500 * time.Microsecond,
+	}, offsets)
+}
+
+func Test_NewStartSkew(t *testing.T) {
+	skew, spread := NewStartSkew(nil, nil)
+	assert.Nil(t, skew)
+	assert.Zero(t, spread)
+
+	skew, spread = NewStartSkew(map[string]time.Duration{
+		"10.0.0.1": 4 * time.Millisecond,
+		"10.0.0.2": 9 * time.Millisecond,
+		"10.0.0.3": 12500 * time.Microsecond,
+	}, map[string]string{
+		"10.0.0.1": "test-1",
+		"10.0.0.2": "test-2",
+	})
+
+	assert.Equal(t, 8500*time.Microsecond, spread)
+	assert.Equal(t, &v1alpha1.StartSkew{
+		Runners: map[string]string{
+			"test-1":   "4ms",
+			"test-2":   "9ms",
+			"10.0.0.3": "12.5ms",
+		},
+		Min:    "4ms",
+		Max:    "12.5ms",
+		Spread: "8.5ms",
+	}, skew)
+}
@@ -0,0 +1,65 @@
+package testrun
+
+import (
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StopRequestedAnnotation is set on a runner job once its runner was asked
+// to stop through the REST API. The value is the time of the request.
+const StopRequestedAnnotation = "k6.io/stop-requested"
+
+// LingerAnnotation is set on runner jobs whose runners are started with
+// `--linger`, as the command of the runner may be wrapped in a shell.
+const LingerAnnotation = "k6.io/linger"
+
+// DefaultTerminationTimeout is how long runners are given to stop and
+// flush their outputs by default.
+const DefaultTerminationTimeout = 30 * time.Second
+
+// TerminationTimeout returns how long a runner is given to stop and flush its
+// outputs after a REST stop, before its job is deleted.
+func TerminationTimeout(k6 *v1alpha1.TestRun) time.Duration {
+	if t := k6.GetSpec().Termination; t != nil && t.TimeoutSeconds != nil {
+		return time.Duration(*t.TimeoutSeconds) * time.Second
+	}
+	return DefaultTerminationTimeout
+}
+
+// StopRequestedAt returns the time the runner of the job was asked to stop.
+func StopRequestedAt(job *batchv1.Job) (time.Time, bool) {
+	value, ok := job.Annotations[StopRequestedAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Lingering tells if the runner of the job keeps its REST API up after the
+// test run ends. Such runners have flushed their outputs once they stop
+// running; the others exit after flushing.
+func Lingering(job *batchv1.Job) bool {
+	return job.Annotations[LingerAnnotation] == "true"
+}
+
+// Terminated tells if none of the pods has a k6 container still alive.
+func Terminated(pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name == "k6" && cs.State.Terminated == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package testrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_TerminationTimeout(t *testing.T) {
+	k6 := &v1alpha1.TestRun{}
+	assert.Equal(t, DefaultTerminationTimeout, TerminationTimeout(k6))
+
+	timeout := int32(90)
+	k6.Spec.Termination = &v1alpha1.Termination{TimeoutSeconds: &timeout}
+	assert.Equal(t, 90*time.Second, TerminationTimeout(k6))
+}
+
+func Test_StopRequestedAt(t *testing.T) {
+	job := &batchv1.Job{}
+	_, ok := StopRequestedAt(job)
+	assert.False(t, ok)
+
+	job.Annotations = map[string]string{StopRequestedAnnotation: "2024-05-01T10:00:00Z"}
+	at, ok := StopRequestedAt(job)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), at)
+
+	job.Annotations[StopRequestedAnnotation] = "yesterday"
+	_, ok = StopRequestedAt(job)
+	assert.False(t, ok)
+}
+
+func Test_Lingering(t *testing.T) {
+	job := &batchv1.Job{}
+	assert.False(t, Lingering(job))
+
+	job.Annotations = map[string]string{LingerAnnotation: "true"}
+	assert.True(t, Lingering(job))
+}
+
+func Test_Terminated(t *testing.T) {
+	running := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-abc"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "k6",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	}
+	exited := *running.DeepCopy()
+	exited.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+	}
+	succeeded := corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodSucceeded}}
+
+	assert.True(t, Terminated(nil))
+	assert.True(t, Terminated([]corev1.Pod{exited, succeeded}))
+	assert.False(t, Terminated([]corev1.Pod{exited, running}))
+}
@@ -0,0 +1,82 @@
+package testrun
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScriptRevision is a hash of the content of the script ConfigMap.
+// All keys are hashed, as the script may import other files of the ConfigMap.
+func ScriptRevision(cm *corev1.ConfigMap) string {
+	keys := make([]string, 0, len(cm.Data)+len(cm.BinaryData))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	for k := range cm.BinaryData {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		// lengths prevent collisions between different splits of the same bytes
+		fmt.Fprintf(h, "%d:%s", len(k), k)
+		if v, ok := cm.Data[k]; ok {
+			fmt.Fprintf(h, "%d:%s", len(v), v)
+		} else {
+			v := cm.BinaryData[k]
+			fmt.Fprintf(h, "%d:", len(v))
+			h.Write(v)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// TriggeredTestName is the name of the TestRun created by the trigger
+// for the change observed at the given time.
+func TriggeredTestName(trigger *v1alpha1.TestRunTrigger, observed metav1.Time) string {
+	return fmt.Sprintf("%s-%d", trigger.Name, observed.Unix())
+}
+
+// NewTriggeredTestRun creates a TestRun from the template of the trigger
+// for the given revision of the script or the workload. The image is that of
+// the rollout and it is passed to the runners if the trigger asks for it.
+func NewTriggeredTestRun(trigger *v1alpha1.TestRunTrigger, revision, image string, observed metav1.Time) *v1alpha1.TestRun {
+	template := trigger.Spec.Template.DeepCopy()
+
+	labels := map[string]string{}
+	for k, v := range template.Metadata.Labels {
+		labels[k] = v
+	}
+	labels[v1alpha1.TriggerLabel] = trigger.Name
+
+	annotations := map[string]string{}
+	for k, v := range template.Metadata.Annotations {
+		annotations[k] = v
+	}
+	if trigger.Spec.Rollout != nil {
+		annotations[v1alpha1.RolloutRevisionAnnotation] = revision
+		if trigger.Spec.Rollout.InjectImage {
+			injectImage(&template.Spec, image)
+		}
+	} else {
+		annotations[v1alpha1.ScriptRevisionAnnotation] = revision
+	}
+
+	return &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        TriggeredTestName(trigger, observed),
+			Namespace:   trigger.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: template.Spec,
+	}
+}
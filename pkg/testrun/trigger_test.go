@@ -0,0 +1,143 @@
+package testrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/grafana/k6-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ScriptRevision(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		Data: map[string]string{
+			"test.js": "export default function () {}",
+			"lib.js":  "export const x = 1",
+		},
+	}
+
+	revision := ScriptRevision(cm)
+	if len(revision) != 16 {
+		t.Errorf("ScriptRevision returned unexpected revision: %s", revision)
+	}
+
+	same := cm.DeepCopy()
+	same.Labels = map[string]string{"changed": "metadata"}
+	if r := ScriptRevision(same); r != revision {
+		t.Errorf("ScriptRevision depends on metadata: %s != %s", r, revision)
+	}
+
+	changedImport := cm.DeepCopy()
+	changedImport.Data["lib.js"] = "export const x = 2"
+	if r := ScriptRevision(changedImport); r == revision {
+		t.Errorf("ScriptRevision did not change with imported file")
+	}
+
+	moved := &corev1.ConfigMap{
+		BinaryData: map[string][]byte{
+			"test.js": []byte("export default function () {}"),
+			"lib.js":  []byte("export const x = 1"),
+		},
+	}
+	if r := ScriptRevision(moved); r != revision {
+		t.Errorf("ScriptRevision depends on encoding of the content: %s != %s", r, revision)
+	}
+}
+
+func Test_NewTriggeredTestRun(t *testing.T) {
+	observed := metav1.NewTime(time.Unix(1700000000, 0))
+
+	trigger := &v1alpha1.TestRunTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nightly",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunTriggerSpec{
+			Template: v1alpha1.TestRunTemplate{
+				Metadata: v1alpha1.PodMetadata{
+					Labels:      map[string]string{"team": "perf"},
+					Annotations: map[string]string{"owner": "perf"},
+				},
+				Spec: v1alpha1.TestRunSpec{
+					Parallelism: 2,
+					Script: v1alpha1.K6Script{
+						ConfigMap: v1alpha1.K6Configmap{
+							Name: "script",
+							File: "test.js",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nightly-1700000000",
+			Namespace: "test",
+			Labels: map[string]string{
+				"team":                "perf",
+				v1alpha1.TriggerLabel: "nightly",
+			},
+			Annotations: map[string]string{
+				"owner":                           "perf",
+				v1alpha1.ScriptRevisionAnnotation: "0123456789abcdef",
+			},
+		},
+		Spec: trigger.Spec.Template.Spec,
+	}
+
+	k6 := NewTriggeredTestRun(trigger, "0123456789abcdef", "", observed)
+	if diff := deep.Equal(expected, k6); diff != nil {
+		t.Errorf("NewTriggeredTestRun returned unexpected data, diff: %s", diff)
+	}
+
+	if _, ok := trigger.Spec.Template.Metadata.Labels[v1alpha1.TriggerLabel]; ok {
+		t.Errorf("NewTriggeredTestRun modified the template")
+	}
+}
+
+func Test_NewTriggeredTestRunRollout(t *testing.T) {
+	trigger := &v1alpha1.TestRunTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cart",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.TestRunTriggerSpec{
+			Rollout: &v1alpha1.RolloutTrigger{
+				Kind:        "Deployment",
+				Name:        "cart",
+				InjectImage: true,
+			},
+			Template: v1alpha1.TestRunTemplate{
+				Spec: v1alpha1.TestRunSpec{
+					Arguments: "--vus 10",
+				},
+			},
+		},
+	}
+
+	k6 := NewTriggeredTestRun(trigger, "shop/cart:v2", "shop/cart:v2", metav1.Now())
+
+	if diff := deep.Equal(map[string]string{v1alpha1.RolloutRevisionAnnotation: "shop/cart:v2"}, k6.Annotations); diff != nil {
+		t.Errorf("NewTriggeredTestRun returned unexpected annotations, diff: %s", diff)
+	}
+
+	expectedEnv := []corev1.EnvVar{
+		{Name: "K6_ROLLOUT_IMAGE", Value: "shop/cart:v2"},
+		{Name: "K6_ROLLOUT_IMAGE_TAG", Value: "v2"},
+	}
+	if diff := deep.Equal(expectedEnv, k6.Spec.Runner.Env); diff != nil {
+		t.Errorf("NewTriggeredTestRun returned unexpected env, diff: %s", diff)
+	}
+
+	if k6.Spec.Arguments != "--vus 10 --tag rollout_image_tag=v2" {
+		t.Errorf("NewTriggeredTestRun returned unexpected arguments: %s", k6.Spec.Arguments)
+	}
+
+	if len(trigger.Spec.Template.Spec.Runner.Env) > 0 {
+		t.Errorf("NewTriggeredTestRun modified the template")
+	}
+}
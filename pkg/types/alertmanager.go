@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+// Alertmanager webhook types.
+// See https://prometheus.io/docs/alerting/latest/configuration/#webhook_config
+
+// AlertmanagerMessage is the body of a notification sent by Alertmanager.
+type AlertmanagerMessage struct {
+	Version  string  `json:"version"`
+	Status   string  `json:"status"`
+	Receiver string  `json:"receiver"`
+	Alerts   []Alert `json:"alerts"`
+}
+
+// Alert is a single alert of the notification.
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+const (
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+)
@@ -8,6 +8,12 @@ import (
 )
 
 func UpdateCondition(conditions *[]metav1.Condition, conditionType string, conditionStatus metav1.ConditionStatus) {
+	UpdateConditionWithMessage(conditions, conditionType, conditionStatus, "")
+}
+
+// UpdateConditionWithMessage is UpdateCondition that also sets a human-readable
+// message of the condition.
+func UpdateConditionWithMessage(conditions *[]metav1.Condition, conditionType string, conditionStatus metav1.ConditionStatus, message string) {
 	reason, ok := reasons[conditionType+string(conditionStatus)]
 	if !ok {
 		panic(fmt.Sprintf("Invalid condition type and status! `%s` - this should never happen!", conditionType+string(conditionStatus)))
@@ -17,7 +23,7 @@ func UpdateCondition(conditions *[]metav1.Condition, conditionType string, condi
 		Status:             conditionStatus,
 		LastTransitionTime: metav1.Now(),
 		Reason:             reason,
-		Message:            "",
+		Message:            message,
 	})
 }
 
@@ -47,10 +53,19 @@ func SetIfNewer(cond *[]metav1.Condition,
 			// Additionally: condition should never return to Unknown status
 			// unless it's newly created.
 
+			//
+			// The message may change without a transition, e.g. to report
+			// progress: then the status and the timestamp are the same.
+
 			if proposedCondition.Status != metav1.ConditionUnknown {
 				if existingCondition.LastTransitionTime.UnixNano() < proposedCondition.LastTransitionTime.UnixNano() {
 					meta.SetStatusCondition(cond, proposedCondition)
 					isNewer = true
+				} else if existingCondition.Status == proposedCondition.Status &&
+					existingCondition.LastTransitionTime.Equal(&proposedCondition.LastTransitionTime) &&
+					existingCondition.Message != proposedCondition.Message {
+					meta.SetStatusCondition(cond, proposedCondition)
+					isNewer = true
 				}
 			}
 		}
@@ -97,4 +112,34 @@ var reasons = map[string]string{
 	"CloudTestRunAbortedUnknown": "CloudTestRunAbortedUnknown",
 	"CloudTestRunAbortedTrue":    "CloudTestRunAbortedTrue",
 	"CloudTestRunAbortedFalse":   "CloudTestRunAbortedFalse",
+
+	"ReportGeneratedUnknown": "ReportGeneratedUnknown",
+	"ReportGeneratedTrue":    "ReportGeneratedTrue",
+	"ReportGeneratedFalse":   "ReportGeneratedFalse",
+
+	"InitializerFailedTrue": "InitializerFailedTrue",
+
+	"TestRunPausedTrue":  "TestRunPausedTrue",
+	"TestRunPausedFalse": "TestRunPausedFalse",
+
+	"RunnersAdmittedTrue":  "RunnersAdmittedTrue",
+	"RunnersAdmittedFalse": "RunnersAdmittedFalse",
+
+	"RunnersScheduledTrue":  "RunnersScheduledTrue",
+	"RunnersScheduledFalse": "RunnersScheduledFalse",
+
+	"StartSkewExceededTrue":  "StartSkewExceededTrue",
+	"StartSkewExceededFalse": "StartSkewExceededFalse",
+
+	"SpreadUnsatisfiableTrue":  "SpreadUnsatisfiableTrue",
+	"SpreadUnsatisfiableFalse": "SpreadUnsatisfiableFalse",
+
+	"ScriptGrantedTrue":  "ScriptGrantedTrue",
+	"ScriptGrantedFalse": "ScriptGrantedFalse",
+
+	"PermissionsGrantedTrue":  "PermissionsGrantedTrue",
+	"PermissionsGrantedFalse": "PermissionsGrantedFalse",
+
+	"EgressRestrictedTrue":  "EgressRestrictedTrue",
+	"EgressRestrictedFalse": "EgressRestrictedFalse",
 }
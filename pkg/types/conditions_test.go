@@ -173,6 +173,62 @@ func Test_SetIfNewer(t *testing.T) {
 				},
 			},
 		},
+		{
+			"changing message without change of condition should be successful",
+			&[]metav1.Condition{
+				metav1.Condition{
+					Type:               "cond",
+					Status:             metav1.ConditionFalse,
+					LastTransitionTime: t1,
+					Message:            "1/2",
+				},
+			},
+			&[]metav1.Condition{
+				metav1.Condition{
+					Type:               "cond",
+					Status:             metav1.ConditionFalse,
+					LastTransitionTime: t1,
+					Message:            "2/2",
+				},
+			},
+			true,
+			[]metav1.Condition{
+				metav1.Condition{
+					Type:               "cond",
+					Status:             metav1.ConditionFalse,
+					LastTransitionTime: t1,
+					Message:            "2/2",
+				},
+			},
+		},
+		{
+			"changing message with an older timestamp should be negative",
+			&[]metav1.Condition{
+				metav1.Condition{
+					Type:               "cond",
+					Status:             metav1.ConditionFalse,
+					LastTransitionTime: t2,
+					Message:            "2/2",
+				},
+			},
+			&[]metav1.Condition{
+				metav1.Condition{
+					Type:               "cond",
+					Status:             metav1.ConditionFalse,
+					LastTransitionTime: t1,
+					Message:            "1/2",
+				},
+			},
+			false,
+			[]metav1.Condition{
+				metav1.Condition{
+					Type:               "cond",
+					Status:             metav1.ConditionFalse,
+					LastTransitionTime: t2,
+					Message:            "2/2",
+				},
+			},
+		},
 		{
 			"changing condition True -> Unknown should be negative even if timestamp increased",
 			&[]metav1.Condition{
@@ -10,6 +10,8 @@ type CLI struct {
 	ArchiveArgs string
 	// k6-operator doesn't care for most values of CLI arguments to k6, with an exception of cloud output
 	HasCloudOut bool
+	// and log format, as runners log in JSON unless told otherwise
+	HasLogFormat bool
 }
 
 func ParseCLI(arguments string) *CLI {
@@ -38,6 +40,10 @@ func ParseCLI(arguments string) *CLI {
 		if args[i][0] == '-' {
 			end := lastArgV(i+1, args)
 
+			if args[i] == "--log-format" || strings.HasPrefix(args[i], "--log-format=") {
+				cli.HasLogFormat = true
+			}
+
 			switch args[i] {
 			case "-o", "--out":
 				for j := 0; j < end; j++ {
@@ -69,6 +69,22 @@ func Test_ParseCLI(t *testing.T) {
 				HasCloudOut: true,
 			},
 		},
+		{
+			"LogFormatArgs",
+			"--vus 10 --log-format raw",
+			CLI{
+				ArchiveArgs:  "--vus 10 --log-format raw",
+				HasLogFormat: true,
+			},
+		},
+		{
+			"LogFormatAssignedArgs",
+			"--log-format=raw --vus 10",
+			CLI{
+				ArchiveArgs:  "--log-format=raw --vus 10",
+				HasLogFormat: true,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -79,6 +95,7 @@ func Test_ParseCLI(t *testing.T) {
 
 			assert.Equal(t, test.cli.ArchiveArgs, cli.ArchiveArgs)
 			assert.Equal(t, test.cli.HasCloudOut, cli.HasCloudOut)
+			assert.Equal(t, test.cli.HasLogFormat, cli.HasLogFormat)
 		})
 	}
 }
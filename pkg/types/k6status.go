@@ -1,6 +1,10 @@
 package types
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	k6api "go.k6.io/k6/api/v1"
+)
 
 // k6 REST API types.
 // TODO: refactor with existing definitions in k6 api/v1?
@@ -33,3 +37,26 @@ type setUpData struct {
 type setupResponseAttributes struct {
 	Data json.RawMessage `json:"data"`
 }
+
+// GroupsAPIResponse is the response of k6 REST API on `GET /v1/groups`.
+type GroupsAPIResponse struct {
+	Data []struct {
+		Attributes k6api.Group `json:"attributes"`
+	} `json:"data"`
+}
+
+// Groups extracts the list of groups from the response.
+func (r GroupsAPIResponse) Groups() []k6api.Group {
+	groups := make([]k6api.Group, len(r.Data))
+	for i := range r.Data {
+		groups[i] = r.Data[i].Attributes
+	}
+	return groups
+}
+
+// RunnerResults holds the results retrieved from a single runner.
+type RunnerResults struct {
+	Hostname string
+	Metrics  []k6api.Metric
+	Groups   []k6api.Group
+}
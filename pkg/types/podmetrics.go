@@ -0,0 +1,27 @@
+package types
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Metrics API types: only the fields used by the operator.
+// See k8s.io/metrics/pkg/apis/metrics/v1beta1
+
+// PodMetricsList is a list of PodMetrics.
+type PodMetricsList struct {
+	Items []PodMetrics `json:"items"`
+}
+
+// PodMetrics is the latest resource usage of a pod.
+type PodMetrics struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Timestamp         metav1.Time        `json:"timestamp"`
+	Containers        []ContainerMetrics `json:"containers"`
+}
+
+// ContainerMetrics is the resource usage of a container.
+type ContainerMetrics struct {
+	Name  string              `json:"name"`
+	Usage corev1.ResourceList `json:"usage"`
+}